@@ -44,11 +44,16 @@ func App() *buffalo.App {
 		// app.Use(forceSSL())
 
 		// Log request parameters (filters apply).
+		// Tokens can be passed in the query string when subscribing to an encounter, so they are filtered too.
+		paramlogger.ParameterExclusionList = append(paramlogger.ParameterExclusionList, "token")
 		app.Use(paramlogger.ParameterLogger)
 
 		// Set the request content type to JSON
 		app.Use(contenttype.Set("application/json"))
 
+		// Pushes encounter events to live subscribers once the transaction below commits
+		app.Use(EventPublisherMiddleware)
+
 		// Wraps each request in a transaction.
 		//  c.Value("tx").(*pop.Connection)
 		// Remove to disable this.
@@ -57,6 +62,10 @@ func App() *buffalo.App {
 		app.GET("/", HomeHandler)
 
 		app.POST("/auth/token", GenerateToken)
+		app.POST("/auth/refresh", RefreshToken)
+		app.POST("/auth/forgot", ForgotPassword)
+		app.POST("/auth/reset", ResetPassword)
+		app.GET("/.well-known/jwks.json", JWKS)
 
 		auth := app.Group("/auth")
 		auth.Use(RestrictedHandlerMiddleware)
@@ -81,7 +90,13 @@ func App() *buffalo.App {
 		player.DELETE("/{player_id}/character/{id}", CharacterDelete)     // Delete
 		player.GET("/{player_id}/character/{id}/delete", CharacterDelete) // Delete
 
+		// the history says who changed what, so only logged in users get to see it
+		history := app.Group("/character")
+		history.Use(RestrictedHandlerMiddleware)
+
 		app.GET("/character/{character_id}/sheet_entries", SheetEntryList)                        // Read
+		history.GET("/{character_id}/sheet_history", SheetHistoryList)                            // List all changes
+		history.GET("/{character_id}/sheet_as_of", SheetAsOf)                                     // Read as it was at ?at=
 		player.GET("/{player_id}/character/{character_id}/sheet_entries", SheetEntryList)         // List all
 		player.GET("/{player_id}/character/{character_id}/sheet_entry/{id}", SheetEntryList)      // Read
 		player.POST("/{player_id}/character/{character_id}/sheet_entry", SheetEntryCreate)        // New
@@ -90,13 +105,96 @@ func App() *buffalo.App {
 		player.PUT("/{player_id}/character/{character_id}/sheet_entries", SheetEntriesUpdate)     // New
 		player.DELETE("/{player_id}/character/{character_id}/sheet_entry/{id}", SheetEntryDelete) // Delete
 
+		app.GET("/character/{character_id}/snapshots", SheetSnapshotList)                                // List all
+		app.GET("/character/{character_id}/snapshot/{id}", SheetSnapshotList)                            // Read
+		app.GET("/character/{character_id}/snapshot/{id}/diff", SheetSnapshotDiff)                       // Compare with the current sheet
+		player.POST("/{player_id}/character/{character_id}/snapshot", SheetSnapshotCreate)               // New
+		player.POST("/{player_id}/character/{character_id}/snapshot/{id}/restore", SheetSnapshotRestore) // Restore
+
+		app.GET("/character/{character_id}/rolls", RollList)                  // List all
+		player.GET("/{player_id}/character/{character_id}/rolls", RollList)   // List all
+		player.POST("/{player_id}/character/{character_id}/roll", RollCreate) // New
+
+		app.GET("/rulesets", RulesetList) // List all
+
+		encounter := app.Group("/encounter")
+		encounter.Use(RestrictedHandlerMiddleware)
+
+		app.GET("/encounters", EncounterList)                                              // List all
+		app.GET("/encounter/{id}", EncounterList)                                          // Read
+		app.GET("/encounter/{id}/participants", EncounterParticipantList)                  // List all
+		encounter.POST("/", EncounterCreate)                                               // New
+		encounter.PUT("/{id}", EncounterUpdate)                                            // Update
+		encounter.POST("/{id}/participant", EncounterParticipantCreate)                    // Join by character ID
+		encounter.POST("/{id}/participant/{server}/{name}", EncounterParticipantCreate)    // Join by server and name
+		encounter.DELETE("/{id}/participant/{participant_id}", EncounterParticipantDelete) // Leave
+		encounter.POST("/{id}/start", EncounterStart)                                      // Start
+		encounter.POST("/{id}/next", EncounterNextTurn)                                    // Next turn
+		encounter.POST("/{id}/close", EncounterClose)                                      // Finish
+
+		app.GET("/encounter/{id}/initiative", EncounterInitiativeList)                                      // Read
+		encounter.POST("/{id}/initiative", EncounterInitiativeRoll)                                         // Re-roll everyone
+		encounter.PUT("/{id}/initiative", EncounterInitiativeReorder)                                       // Reorder by hand
+		encounter.POST("/{id}/participant/{participant_id}/initiative", EncounterParticipantInitiativeRoll) // Re-roll one
+		encounter.POST("/{id}/participant/{participant_id}/delay", EncounterParticipantDelay)               // Delay
+
+		app.GET("/encounter/{id}/state", EncounterStateShow)                                    // Read
+		app.GET("/encounter/{id}/health_changes", EncounterHealthChangeList)                    // List all
+		encounter.POST("/{id}/participant/{participant_id}/damage", EncounterParticipantDamage) // Damage
+		encounter.POST("/{id}/participant/{participant_id}/heal", EncounterParticipantHeal)     // Heal
+		encounter.PUT("/{id}/participant/{participant_id}/status", EncounterParticipantStatus)  // Down, flee or return
+
+		app.GET("/encounter/{id}/conditions", EncounterConditionList)                                             // List all
+		encounter.POST("/{id}/participant/{participant_id}/condition", EncounterConditionCreate)                  // Apply
+		encounter.DELETE("/{id}/participant/{participant_id}/condition/{condition_id}", EncounterConditionDelete) // Remove
+
+		app.GET("/encounter/{id}/attacks", EncounterAttackList)            // List all
+		app.GET("/encounter/{id}/attack/{attack_id}", EncounterAttackList) // Read
+		encounter.POST("/{id}/attack", EncounterAttackCreate)              // New
+
+		app.GET("/encounter/{id}/log", EncounterLogList)          // List all
+		app.GET("/encounter/{id}/log/export", EncounterLogExport) // Export as emotes
+		encounter.GET("/{id}/live", EncounterLive)                // Subscribe over a WebSocket
+		encounter.GET("/{id}/stream", EncounterStream)            // Subscribe with Server-Sent Events
+		encounter.Middleware.Skip(popmw.Transaction(models.DB), EncounterLive, EncounterStream)
+
+		// only game masters and admins manage the skill catalogue
+		catalogue := []string{models.RoleGameMaster, models.RoleAdmin}
+
+		skill := app.Group("/skill")
+		skill.Use(RoleRestrictedHandlerMiddleware(catalogue...))
+
 		app.GET("/skills", SkillList)                          // List all
+		app.GET("/skills/tree", SkillTree)                     // Read the whole hierarchy
 		app.GET("/skill/{id}", SkillList)                      // Read
 		app.GET("/skill/{parent_id}/subskills", SkillList)     // Read all subskills
 		app.GET("/skill/{parent_id}/subskill/{id}", SkillList) // Read subskill
-		app.POST("/skill", SkillCreate)                        // New
-		app.PUT("/skill/{id}", SkillUpdate)                    // Update
-		app.DELETE("/skill/{id}", SkillDelete)                 // Delete
+		skill.POST("/", SkillCreate)                           // New
+		skill.PUT("/{id}", SkillUpdate)                        // Update
+		skill.DELETE("/{id}", SkillDelete)                     // Delete
+
+		skillSet := app.Group("/skill_set")
+		skillSet.Use(RoleRestrictedHandlerMiddleware(catalogue...))
+
+		app.GET("/skill_sets", SkillSetList)                   // List all
+		app.GET("/skill_set/{id}", SkillSetList)               // Read
+		app.GET("/skill_set/{skill_set_id}/skills", SkillList) // List the set's skills
+		skillSet.POST("/", SkillSetCreate)                     // New
+		skillSet.PUT("/{id}", SkillSetUpdate)                  // Update
+
+		app.GET("/skill/{id}/prerequisites", SkillPrerequisiteList)                   // List all
+		skill.POST("/{id}/prerequisite", SkillPrerequisiteCreate)                     // New
+		skill.DELETE("/{id}/prerequisite/{prerequisite_id}", SkillPrerequisiteDelete) // Delete
+
+		character := app.Group("/character")
+		character.Use(RoleRestrictedHandlerMiddleware(models.RoleGameMaster, models.RoleAdmin))
+
+		character.PUT("/{id}/point_budget", CharacterPointBudgetUpdate) // Change how many points a character can spend
+
+		admin := app.Group("/admin")
+		admin.Use(RoleRestrictedHandlerMiddleware(models.RoleAdmin))
+
+		admin.PUT("/player/{player_id}/role", UserRoleUpdate) // Change a player's role
 
 	}
 
@@ -0,0 +1,127 @@
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/dosaki/emote_combat_server/helpers"
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/pop"
+)
+
+func getAttackBody(c buffalo.Context) models.AttackJSON {
+	request := c.Request()
+	decoder := json.NewDecoder(request.Body)
+	body := models.AttackJSON{}
+	err := decoder.Decode(&body)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+// EncounterAttackCreate resolves an attack between two participants. Either the host or the attacker's player can call it.
+func EncounterAttackCreate(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	id, perr := helpers.Param(c, "id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoEncounterIDError}))
+	}
+
+	encounter, err := services.GetEncounter(tx, id)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	body := getAttackBody(c)
+	attacker, err := services.GetParticipant(tx, encounter.ID, body.AttackerID.String())
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	defender, err := services.GetParticipant(tx, encounter.ID, body.DefenderID.String())
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	user, ok := c.Value("user").(models.User)
+	if !ok {
+		return c.Error(http.StatusUnauthorized, fmt.Errorf(messages.InvalidTokenOrUnauthorizedError))
+	}
+	if user.ID != encounter.HostID {
+		var characters []models.Character
+		cerr := tx.Where("player_id = ?", user.ID).Where("id = ?", attacker.CharacterID).All(&characters)
+		if cerr != nil || len(characters) == 0 {
+			return c.Error(http.StatusForbidden, fmt.Errorf(messages.NotAttackerError))
+		}
+	}
+
+	// skills named rather than given by ID are looked up in each side's own skill set
+	attackingCharacter, err := services.GetCharacter(tx, attacker.CharacterID)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	defendingCharacter, err := services.GetCharacter(tx, defender.CharacterID)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	attackSkill, err := services.FindSkill(tx, attackingCharacter.SkillSetID, body.AttackSkillID, body.AttackSkill)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	defenseSkill, err := services.FindSkill(tx, defendingCharacter.SkillSetID, body.DefenseSkillID, body.DefenseSkill)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	result, err := services.ResolveAttack(tx, encounter, attacker, defender, attackSkill, defenseSkill)
+	if err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(201, r.JSON(result))
+}
+
+// EncounterAttackList lists every attack in an encounter or reads a single one, with both rolls in full.
+func EncounterAttackList(c buffalo.Context) error {
+	id, perr := helpers.Param(c, "id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoEncounterIDError}))
+	}
+
+	query := models.DB.Where("encounter_id = ?", id)
+	attackID, aerr := helpers.Param(c, "attack_id")
+	if aerr == nil {
+		query = query.Where("id = ?", attackID)
+	}
+
+	var attacks []models.Attack
+	err := query.Order("created_at asc").All(&attacks)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": messages.ProblemGettingAttacksError}))
+	}
+	if aerr == nil && len(attacks) == 0 {
+		return c.Render(404, r.JSON(map[string]string{"message": messages.AttackNotFoundError}))
+	}
+
+	results := []services.AttackResult{}
+	for _, attack := range attacks {
+		result, err := services.GetAttackResult(models.DB, attack)
+		if err != nil {
+			return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+		}
+		results = append(results, result)
+	}
+
+	if aerr == nil {
+		return c.Render(200, r.JSON(results[0]))
+	}
+	return c.Render(200, r.JSON(results))
+}
@@ -1,21 +1,50 @@
 package actions
 
 import (
+	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"strconv"
 	"strings"
-	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/dosaki/emote_combat_server/messages"
 	"github.com/dosaki/emote_combat_server/models"
 	"github.com/dosaki/emote_combat_server/services"
 	"github.com/gobuffalo/buffalo"
-	"github.com/gobuffalo/envy"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+func getRefreshTokenBody(c buffalo.Context) models.RefreshTokenJSON {
+	request := c.Request()
+	decoder := json.NewDecoder(request.Body)
+	body := models.RefreshTokenJSON{}
+	err := decoder.Decode(&body)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+// renderTokens - sends a new access token along with the refresh token that goes with it
+func renderTokens(c buffalo.Context, status int, refreshTokenString string, refreshToken models.RefreshToken) error {
+	tokenString, expiry, err := services.SignAccessToken(refreshToken.UserID, refreshToken.FamilyID)
+	if err != nil {
+		fmt.Println(err)
+		return c.Render(http.StatusServiceUnavailable, r.JSON(map[string]string{"message": messages.TokenUnavailableError}))
+	}
+
+	return c.Render(status, r.JSON(map[string]string{
+		"token":            tokenString,
+		"expiresAt":        strconv.FormatInt(expiry.Unix(), 10),
+		"refreshToken":     refreshTokenString,
+		"refreshExpiresAt": strconv.FormatInt(refreshToken.ExpiresAt.Unix(), 10),
+		"playerId":         refreshToken.UserID.String(),
+	}))
+}
+
 // GenerateToken default implementation.
 func GenerateToken(c buffalo.Context) error {
 	u := getUserAuthBody(c)
@@ -27,28 +56,31 @@ func GenerateToken(c buffalo.Context) error {
 		return c.Render(http.StatusBadRequest, r.JSON(map[string]string{"message": "Unable to authenticate."}))
 	}
 
-	expiry := time.Now().Add(time.Minute * 60).Unix()
-	claims := jwt.StandardClaims{
-		ExpiresAt: expiry,
-		Id:        users[0].ID.String(),
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signingKey, err := ioutil.ReadFile(envy.Get("JWT_KEY_PATH", ""))
+
+	refreshTokenString, refreshToken, err := services.IssueRefreshToken(tx, users[0].ID, UUID.Nil)
 	if err != nil {
-		fmt.Println("could not open jwt key", err)
-		return c.Render(http.StatusServiceUnavailable, r.JSON(map[string]string{"message": "Token generation is unavailable. Please contact the administrator."}))
+		return c.Render(http.StatusInternalServerError, r.JSON(map[string]string{"message": err.Error()}))
 	}
-	tokenString, err := token.SignedString(signingKey)
-	if err != nil {
-		fmt.Println("could not sign token", err)
-		return c.Render(http.StatusServiceUnavailable, r.JSON(map[string]string{"message": "Token generation is unavailable. Please contact the administrator."}))
+	return renderTokens(c, 201, refreshTokenString, refreshToken)
+}
+
+// RefreshToken trades a refresh token in for a new access token and a new refresh token.
+func RefreshToken(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
 	}
 
-	return c.Render(201, r.JSON(map[string]string{
-		"token":     tokenString,
-		"expiresAt": strconv.FormatInt(expiry, 10),
-		"playerId":  users[0].ID.String(),
-	}))
+	body := getRefreshTokenBody(c)
+	refreshTokenString, refreshToken, err := services.RotateRefreshToken(tx, body.RefreshToken)
+	if err != nil {
+		return c.Render(http.StatusUnauthorized, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return renderTokens(c, 201, refreshTokenString, refreshToken)
 }
 
 // DestroyToken - Destroys the current token effectively logging out the user
@@ -58,10 +90,22 @@ func DestroyToken(c buffalo.Context) error {
 		return c.Render(http.StatusBadRequest, r.JSON(map[string]string{"message": "Attempting to log out a user who's already logged out."}))
 	}
 
-	_, err := jwt.Parse(tokenString, services.ParseToken)
+	token, err := jwt.Parse(tokenString, services.ParseToken)
 	if err != nil {
 		return c.Error(http.StatusUnauthorized, fmt.Errorf("Invalid user/token pair"))
 	}
-	services.InvalidateToken(tokenString)
+	if err := services.InvalidateToken(tokenString, services.TokenExpiry(token)); err != nil {
+		return c.Render(http.StatusInternalServerError, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	// the login's refresh tokens can't be used to get back in either
+	if familyID := services.TokenSession(token); familyID != UUID.Nil {
+		tx, ok := c.Value("tx").(*pop.Connection)
+		if !ok {
+			panic(messages.NoConnectionError)
+		}
+		if err := services.RevokeRefreshFamily(tx, familyID); err != nil {
+			return c.Render(http.StatusInternalServerError, r.JSON(map[string]string{"message": err.Error()}))
+		}
+	}
 	return c.Render(http.StatusOK, r.JSON(map[string]string{}))
 }
@@ -5,8 +5,11 @@ import (
     "fmt"
 
     "github.com/dosaki/emote_combat_server/helpers"
+    "github.com/dosaki/emote_combat_server/messages"
     "github.com/dosaki/emote_combat_server/models"
+    "github.com/dosaki/emote_combat_server/services"
     "github.com/gobuffalo/buffalo"
+    "github.com/gobuffalo/nulls"
     "github.com/gobuffalo/pop"
     "github.com/gobuffalo/uuid"
 )
@@ -48,6 +51,15 @@ func CharacterCreate(c buffalo.Context) error {
     character.Gender = body.Gender
     character.IngameName = body.IngameName
     character.Server = body.Server
+    // players don't pick their own budget, a game master can change it afterwards
+    character.PointBudget = services.DefaultPointBudget()
+    character.SkillSetID = body.SkillSetID
+    if character.SkillSetID == uuid.Nil {
+        character.SkillSetID = models.DefaultSkillSetID
+    }
+    if _, err := services.GetSkillSet(tx, character.SkillSetID); err != nil {
+        return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+    }
 
     var users []models.User
     err := models.DB.Where("id = ?", body.PlayerID).All(&users)
@@ -59,7 +71,7 @@ func CharacterCreate(c buffalo.Context) error {
     if tx.Create(&character) == nil {
 
         var skills []models.Skill
-        skillsErr := models.DB.All(&skills)
+        skillsErr := models.DB.Where("skill_set_id = ?", character.SkillSetID).All(&skills)
 
         if skillsErr == nil {
             for _, skill := range skills {
@@ -68,9 +80,16 @@ func CharacterCreate(c buffalo.Context) error {
                     SkillID:     skill.ID,
                     Value:       skill.StartingValue,
                 }
-                if tx.Create(&skillEntry) != nil {
+                verrs, err := tx.ValidateAndCreate(&skillEntry)
+                if err != nil {
                     return c.Render(400, r.JSON(map[string]string{}))
                 }
+                if verrs.HasAny() {
+                    return c.Render(400, r.JSON(verrs))
+                }
+                if services.RecordSheetChange(tx, currentUserID(c), skillEntry, nulls.Int{}, nulls.NewInt(skillEntry.Value)) != nil {
+                    return c.Render(500, r.JSON(map[string]string{"message": messages.UnknownError}))
+                }
             }
         } else {
             return c.Render(400, r.JSON(map[string]string{}))
@@ -128,6 +147,38 @@ func CharacterUpdate(c buffalo.Context) error {
     return c.Render(500, r.JSON(map[string]string{"message": "Unknown error."}))
 }
 
+// CharacterPointBudgetUpdate lets a game master or an admin change how many points a character can spend.
+// A null budget lets the character spend freely.
+func CharacterPointBudgetUpdate(c buffalo.Context) error {
+    puuid, perr := helpers.Param(c, "id")
+    if perr != nil {
+        return c.Render(400, r.JSON(map[string]string{"message": "No ID provided."}))
+    }
+
+    characterUUID, uuiderr := uuid.FromString(puuid)
+    if uuiderr != nil {
+        return c.Render(400, r.JSON(map[string]string{"message": "Bad character UUID."}))
+    }
+
+    tx, ok := c.Value("tx").(*pop.Connection)
+    if !ok {
+        panic(messages.NoConnectionError)
+    }
+
+    character, err := services.GetCharacter(tx, characterUUID)
+    if err != nil {
+        return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+    }
+
+    body := getCharacterBody(c)
+    character.PointBudget = body.PointBudget
+    if tx.Save(&character) == nil {
+        return c.Render(200, r.JSON(character))
+    }
+
+    return c.Render(500, r.JSON(map[string]string{"message": messages.UnknownError}))
+}
+
 // CharacterDelete default implementation.
 func CharacterDelete(c buffalo.Context) error {
     puuid, perr := helpers.Param(c, "id")
@@ -0,0 +1,131 @@
+package actions
+
+import (
+	"encoding/json"
+
+	"github.com/dosaki/emote_combat_server/helpers"
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+)
+
+func getConditionBody(c buffalo.Context) models.Condition {
+	request := c.Request()
+	decoder := json.NewDecoder(request.Body)
+	body := models.Condition{}
+	err := decoder.Decode(&body)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+// EncounterConditionCreate puts a condition on a participant.
+func EncounterConditionCreate(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	encounter, herr := getHostedEncounter(c, tx)
+	if herr != nil {
+		return herr
+	}
+	if encounter.Status == models.EncounterFinished {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.EncounterFinishedError}))
+	}
+
+	participantID, perr := helpers.Param(c, "participant_id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoParticipantIDError}))
+	}
+
+	participant, err := services.GetParticipant(tx, encounter.ID, participantID)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	condition, err := services.ApplyCondition(tx, participant, getConditionBody(c))
+	if err != nil {
+		if verrs, ok := err.(*validate.Errors); ok {
+			return c.Render(400, r.JSON(verrs))
+		}
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(201, r.JSON(condition))
+}
+
+// EncounterConditionDelete ends a condition early.
+func EncounterConditionDelete(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	encounter, herr := getHostedEncounter(c, tx)
+	if herr != nil {
+		return herr
+	}
+
+	participantID, perr := helpers.Param(c, "participant_id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoParticipantIDError}))
+	}
+
+	conditionID, cerr := helpers.Param(c, "condition_id")
+	if cerr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoConditionIDError}))
+	}
+
+	participant, err := services.GetParticipant(tx, encounter.ID, participantID)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	condition, err := services.GetCondition(tx, participant.ID, conditionID)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	if err := services.RemoveCondition(tx, &condition); err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(condition))
+}
+
+// EncounterConditionList lists the active conditions of a participant, or of everyone in the encounter.
+// Pass all=true to include conditions that have worn off.
+func EncounterConditionList(c buffalo.Context) error {
+	id, perr := helpers.Param(c, "id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoEncounterIDError}))
+	}
+
+	encounter, err := services.GetEncounter(models.DB, id)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	all, _ := helpers.Param(c, "all")
+	participants, err := services.GetParticipants(models.DB, encounter.ID)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	participantID, pierr := helpers.Param(c, "participant_id")
+	conditions := []models.Condition{}
+	for _, participant := range participants {
+		if pierr == nil && participant.ID.String() != participantID {
+			continue
+		}
+		participantConditions, err := services.GetConditions(models.DB, participant.ID, all != "true")
+		if err != nil {
+			return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+		}
+		conditions = append(conditions, participantConditions...)
+	}
+	return c.Render(200, r.JSON(conditions))
+}
@@ -0,0 +1,341 @@
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/dosaki/emote_combat_server/helpers"
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
+	"github.com/dosaki/emote_combat_server/services/ruleset"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/uuid"
+)
+
+func getEncounterBody(c buffalo.Context) models.Encounter {
+	request := c.Request()
+	decoder := json.NewDecoder(request.Body)
+	body := models.Encounter{}
+	err := decoder.Decode(&body)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+func getEncounterUpdateBody(c buffalo.Context) models.EncounterUpdateJSON {
+	request := c.Request()
+	decoder := json.NewDecoder(request.Body)
+	body := models.EncounterUpdateJSON{}
+	err := decoder.Decode(&body)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+func getParticipantBody(c buffalo.Context) models.EncounterParticipant {
+	request := c.Request()
+	decoder := json.NewDecoder(request.Body)
+	body := models.EncounterParticipant{}
+	err := decoder.Decode(&body)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+// getHostedEncounter - loads the encounter in the URL, making sure the current user is hosting it
+func getHostedEncounter(c buffalo.Context, tx *pop.Connection) (models.Encounter, error) {
+	id, perr := helpers.Param(c, "id")
+	if perr != nil {
+		return models.Encounter{}, c.Error(http.StatusBadRequest, fmt.Errorf(messages.NoEncounterIDError))
+	}
+
+	encounter, err := services.GetEncounter(tx, id)
+	if err != nil {
+		return models.Encounter{}, c.Error(http.StatusNotFound, err)
+	}
+
+	user, ok := c.Value("user").(models.User)
+	if !ok || user.ID != encounter.HostID {
+		return models.Encounter{}, c.Error(http.StatusForbidden, fmt.Errorf(messages.NotEncounterHostError))
+	}
+	return encounter, nil
+}
+
+// checkEncounterSkills - makes sure the skills an encounter is set to use exist
+func checkEncounterSkills(tx *pop.Connection, encounter models.Encounter) error {
+	for _, skillID := range []uuid.UUID{encounter.InitiativeSkillID, encounter.HealthSkillID} {
+		if skillID == uuid.Nil {
+			continue
+		}
+		if _, err := services.FindSkill(tx, uuid.Nil, skillID, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EncounterCreate opens a new encounter hosted by the current user.
+func EncounterCreate(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	user, ok := c.Value("user").(models.User)
+	if !ok {
+		return c.Error(http.StatusUnauthorized, fmt.Errorf(messages.InvalidTokenOrUnauthorizedError))
+	}
+
+	body := getEncounterBody(c)
+	encounter := models.Encounter{}
+	encounter.Name = body.Name
+	encounter.HostID = user.ID
+	encounter.Status = models.EncounterOpen
+	encounter.InitiativeSkillID = body.InitiativeSkillID
+	encounter.HealthSkillID = body.HealthSkillID
+	encounter.BaseHealth = body.BaseHealth
+	if encounter.BaseHealth == 0 && encounter.HealthSkillID == uuid.Nil {
+		encounter.BaseHealth = models.DefaultBaseHealth
+	}
+	encounter.Ruleset = body.Ruleset
+	if len(encounter.Ruleset) == 0 {
+		encounter.Ruleset = ruleset.Default
+	}
+
+	if err := checkEncounterSkills(tx, encounter); err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	verrs, err := tx.ValidateAndCreate(&encounter)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": messages.UnknownError}))
+	}
+	if verrs.HasAny() {
+		return c.Render(400, r.JSON(verrs))
+	}
+
+	message := fmt.Sprintf("%s opens the fight: %s.", user.Name, encounter.Name)
+	if _, err := services.LogEvent(tx, encounter.ID, models.EventCreated, uuid.Nil, message, encounter); err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(201, r.JSON(encounter))
+}
+
+// EncounterUpdate renames an encounter or changes the skill used for initiative.
+func EncounterUpdate(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	encounter, herr := getHostedEncounter(c, tx)
+	if herr != nil {
+		return herr
+	}
+	if encounter.Status == models.EncounterFinished {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.EncounterFinishedError}))
+	}
+
+	// only what was sent changes; a skill is cleared by sending the nil UUID
+	body := getEncounterUpdateBody(c)
+	if body.Name != nil {
+		encounter.Name = *body.Name
+	}
+	if body.InitiativeSkillID != nil {
+		encounter.InitiativeSkillID = *body.InitiativeSkillID
+	}
+	if body.HealthSkillID != nil {
+		encounter.HealthSkillID = *body.HealthSkillID
+	}
+	if body.BaseHealth != nil {
+		encounter.BaseHealth = *body.BaseHealth
+	}
+	if encounter.BaseHealth == 0 && encounter.HealthSkillID == uuid.Nil {
+		encounter.BaseHealth = models.DefaultBaseHealth
+	}
+
+	// the rules can't change once the fight has started
+	if body.Ruleset != nil && len(*body.Ruleset) > 0 && *body.Ruleset != encounter.Ruleset {
+		if encounter.Status != models.EncounterOpen {
+			return c.Render(400, r.JSON(map[string]string{"message": messages.EncounterNotOpenError}))
+		}
+		encounter.Ruleset = *body.Ruleset
+	}
+
+	if err := checkEncounterSkills(tx, encounter); err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	verrs, err := tx.ValidateAndSave(&encounter)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": messages.UnknownError}))
+	}
+	if verrs.HasAny() {
+		return c.Render(400, r.JSON(verrs))
+	}
+	return c.Render(200, r.JSON(encounter))
+}
+
+// EncounterList lists all encounters or reads a single one.
+func EncounterList(c buffalo.Context) error {
+	id, perr := helpers.Param(c, "id")
+	if perr == nil {
+		encounter, err := services.GetEncounter(models.DB, id)
+		if err != nil {
+			return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+		}
+		return c.Render(200, r.JSON(encounter))
+	}
+
+	encounters := []models.Encounter{}
+	query := models.DB.Where("1=1")
+	status, serr := helpers.Param(c, "status")
+	if serr == nil {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at desc").All(&encounters)
+	if err == nil {
+		return c.Render(200, r.JSON(encounters))
+	}
+	return c.Render(500, r.JSON(map[string]string{"message": messages.ProblemGettingEncountersError}))
+}
+
+// EncounterParticipantList lists everyone in an encounter in turn order.
+func EncounterParticipantList(c buffalo.Context) error {
+	id, perr := helpers.Param(c, "id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoEncounterIDError}))
+	}
+
+	encounter, err := services.GetEncounter(models.DB, id)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	participants, err := services.GetParticipants(models.DB, encounter.ID)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(participants))
+}
+
+// EncounterParticipantCreate adds a character to an encounter, either by ID or by server and name.
+func EncounterParticipantCreate(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	encounter, herr := getHostedEncounter(c, tx)
+	if herr != nil {
+		return herr
+	}
+
+	name, nerr := helpers.Param(c, "name")
+	server, serr := helpers.Param(c, "server")
+
+	var character models.Character
+	if nerr == nil && serr == nil {
+		var err error
+		character, err = services.GetCharacterByServerAndName(tx, server, name)
+		if err != nil {
+			return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+		}
+	} else {
+		character.ID = getParticipantBody(c).CharacterID
+	}
+
+	participant, err := services.AddParticipant(tx, encounter, character.ID)
+	if err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(201, r.JSON(participant))
+}
+
+// EncounterParticipantDelete takes a character out of an encounter.
+func EncounterParticipantDelete(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	encounter, herr := getHostedEncounter(c, tx)
+	if herr != nil {
+		return herr
+	}
+
+	participantID, perr := helpers.Param(c, "participant_id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoParticipantIDError}))
+	}
+
+	participant, err := services.GetParticipant(tx, encounter.ID, participantID)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	if err := services.RemoveParticipant(tx, &encounter, participant); err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(encounter))
+}
+
+// EncounterStart starts the fight.
+func EncounterStart(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	encounter, herr := getHostedEncounter(c, tx)
+	if herr != nil {
+		return herr
+	}
+
+	if err := services.StartEncounter(tx, &encounter); err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(encounter))
+}
+
+// EncounterNextTurn hands the turn to the next participant.
+func EncounterNextTurn(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	encounter, herr := getHostedEncounter(c, tx)
+	if herr != nil {
+		return herr
+	}
+
+	if err := services.NextTurn(tx, &encounter); err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(encounter))
+}
+
+// EncounterClose ends the fight.
+func EncounterClose(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	encounter, herr := getHostedEncounter(c, tx)
+	if herr != nil {
+		return herr
+	}
+
+	if err := services.CloseEncounter(tx, &encounter); err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(encounter))
+}
@@ -0,0 +1,65 @@
+package actions
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/dosaki/emote_combat_server/helpers"
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+)
+
+// getLoggedEncounter - loads the encounter in the URL along with its log, optionally only after a given sequence number
+func getLoggedEncounter(c buffalo.Context) (models.Encounter, []models.EncounterEvent, int, error) {
+	id, perr := helpers.Param(c, "id")
+	if perr != nil {
+		return models.Encounter{}, nil, 400, perr
+	}
+
+	encounter, err := services.GetEncounter(models.DB, id)
+	if err != nil {
+		return encounter, nil, 404, err
+	}
+
+	after := 0
+	if a, aerr := helpers.Param(c, "after"); aerr == nil {
+		after, _ = strconv.Atoi(a)
+	}
+
+	events, err := services.GetEvents(models.DB, encounter.ID, after)
+	if err != nil {
+		return encounter, nil, 500, err
+	}
+	return encounter, events, 200, nil
+}
+
+// EncounterLogList lists everything that happened in an encounter, in order.
+func EncounterLogList(c buffalo.Context) error {
+	_, events, code, err := getLoggedEncounter(c)
+	if err != nil {
+		if code == 400 {
+			return c.Render(code, r.JSON(map[string]string{"message": messages.NoEncounterIDError}))
+		}
+		return c.Render(code, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(events))
+}
+
+// EncounterLogExport returns the log as plain text formatted like chat emotes.
+func EncounterLogExport(c buffalo.Context) error {
+	encounter, events, code, err := getLoggedEncounter(c)
+	if err != nil {
+		if code == 400 {
+			return c.Render(code, r.JSON(map[string]string{"message": messages.NoEncounterIDError}))
+		}
+		return c.Render(code, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	return c.Render(200, r.Func("text/plain; charset=utf-8", func(w io.Writer, d render.Data) error {
+		_, err := io.WriteString(w, services.EmoteLog(encounter, events))
+		return err
+	}))
+}
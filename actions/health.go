@@ -0,0 +1,144 @@
+package actions
+
+import (
+	"encoding/json"
+
+	"github.com/dosaki/emote_combat_server/helpers"
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/pop"
+)
+
+func getHealthChangeBody(c buffalo.Context) models.HealthChangeJSON {
+	request := c.Request()
+	decoder := json.NewDecoder(request.Body)
+	body := models.HealthChangeJSON{}
+	err := decoder.Decode(&body)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+func getStatusChangeBody(c buffalo.Context) models.StatusChangeJSON {
+	request := c.Request()
+	decoder := json.NewDecoder(request.Body)
+	body := models.StatusChangeJSON{}
+	err := decoder.Decode(&body)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+// changeHealth - shared by the damage and heal handlers
+func changeHealth(c buffalo.Context, kind string) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	encounter, herr := getHostedEncounter(c, tx)
+	if herr != nil {
+		return herr
+	}
+	if encounter.Status == models.EncounterFinished {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.EncounterFinishedError}))
+	}
+
+	participantID, perr := helpers.Param(c, "participant_id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoParticipantIDError}))
+	}
+
+	participant, err := services.GetParticipant(tx, encounter.ID, participantID)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	body := getHealthChangeBody(c)
+	change, err := services.ApplyHealthChange(tx, &participant, kind, body.Amount, body.Reason)
+	if err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(change))
+}
+
+// EncounterParticipantDamage takes health away from a participant.
+func EncounterParticipantDamage(c buffalo.Context) error {
+	return changeHealth(c, models.HealthDamage)
+}
+
+// EncounterParticipantHeal gives health back to a participant.
+func EncounterParticipantHeal(c buffalo.Context) error {
+	return changeHealth(c, models.HealthHeal)
+}
+
+// EncounterParticipantStatus marks a participant as active, downed or fled.
+func EncounterParticipantStatus(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	encounter, herr := getHostedEncounter(c, tx)
+	if herr != nil {
+		return herr
+	}
+	if encounter.Status == models.EncounterFinished {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.EncounterFinishedError}))
+	}
+
+	participantID, perr := helpers.Param(c, "participant_id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoParticipantIDError}))
+	}
+
+	participant, err := services.GetParticipant(tx, encounter.ID, participantID)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	body := getStatusChangeBody(c)
+	change, err := services.SetStatus(tx, &participant, body.Status, body.Reason)
+	if err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(change))
+}
+
+// EncounterStateShow returns the encounter with everyone's health and status, for drawing health bars.
+func EncounterStateShow(c buffalo.Context) error {
+	id, perr := helpers.Param(c, "id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoEncounterIDError}))
+	}
+
+	encounter, err := services.GetEncounter(models.DB, id)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	state, err := services.GetEncounterState(models.DB, encounter)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(state))
+}
+
+// EncounterHealthChangeList lists every damage, heal and status change in an encounter, oldest first.
+func EncounterHealthChangeList(c buffalo.Context) error {
+	id, perr := helpers.Param(c, "id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoEncounterIDError}))
+	}
+
+	changes := []models.HealthChange{}
+	err := models.DB.Where("encounter_id = ?", id).Order("created_at asc").All(&changes)
+	if err == nil {
+		return c.Render(200, r.JSON(changes))
+	}
+	return c.Render(500, r.JSON(map[string]string{"message": messages.ProblemGettingHealthChangesError}))
+}
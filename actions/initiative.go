@@ -0,0 +1,164 @@
+package actions
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/dosaki/emote_combat_server/helpers"
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/pop"
+)
+
+func getTurnOrderBody(c buffalo.Context) models.TurnOrderJSON {
+	request := c.Request()
+	decoder := json.NewDecoder(request.Body)
+	body := models.TurnOrderJSON{}
+	err := decoder.Decode(&body)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+func getDelayBody(c buffalo.Context) models.DelayJSON {
+	request := c.Request()
+	decoder := json.NewDecoder(request.Body)
+	body := models.DelayJSON{}
+	err := decoder.Decode(&body)
+	// the body is optional: no body means "go last"
+	if err != nil && err != io.EOF {
+		panic(err)
+	}
+	return body
+}
+
+// EncounterInitiativeList lists the participants of an encounter in initiative order.
+func EncounterInitiativeList(c buffalo.Context) error {
+	return EncounterParticipantList(c)
+}
+
+// EncounterInitiativeRoll re-rolls initiative for everyone and re-sorts the turn order.
+func EncounterInitiativeRoll(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	encounter, herr := getHostedEncounter(c, tx)
+	if herr != nil {
+		return herr
+	}
+	if encounter.Status == models.EncounterFinished {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.EncounterFinishedError}))
+	}
+
+	participants, err := services.RollAllInitiative(tx, &encounter, false)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(participants))
+}
+
+// EncounterParticipantInitiativeRoll re-rolls initiative for a single participant and re-sorts the turn order.
+func EncounterParticipantInitiativeRoll(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	encounter, herr := getHostedEncounter(c, tx)
+	if herr != nil {
+		return herr
+	}
+	if encounter.Status == models.EncounterFinished {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.EncounterFinishedError}))
+	}
+
+	participantID, perr := helpers.Param(c, "participant_id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoParticipantIDError}))
+	}
+
+	participants, err := services.GetParticipants(tx, encounter.ID)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	found := false
+	for i := range participants {
+		if participants[i].ID.String() == participantID {
+			found = true
+			if err := services.RollInitiative(tx, encounter, &participants[i]); err != nil {
+				return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+			}
+		}
+	}
+	if !found {
+		return c.Render(404, r.JSON(map[string]string{"message": messages.ParticipantNotFoundError}))
+	}
+
+	ordered, err := services.SortByInitiative(tx, &encounter, participants)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(ordered))
+}
+
+// EncounterInitiativeReorder lets the host set the turn order by hand.
+func EncounterInitiativeReorder(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	encounter, herr := getHostedEncounter(c, tx)
+	if herr != nil {
+		return herr
+	}
+	if encounter.Status == models.EncounterFinished {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.EncounterFinishedError}))
+	}
+
+	body := getTurnOrderBody(c)
+	participants, err := services.ReorderParticipants(tx, &encounter, body.Order)
+	if err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(participants))
+}
+
+// EncounterParticipantDelay moves a participant further down the turn order.
+func EncounterParticipantDelay(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	encounter, herr := getHostedEncounter(c, tx)
+	if herr != nil {
+		return herr
+	}
+	if encounter.Status == models.EncounterFinished {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.EncounterFinishedError}))
+	}
+
+	participantID, perr := helpers.Param(c, "participant_id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoParticipantIDError}))
+	}
+
+	participant, err := services.GetParticipant(tx, encounter.ID, participantID)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	body := getDelayBody(c)
+	participants, err := services.DelayParticipant(tx, &encounter, participant.ID, body.After)
+	if err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(participants))
+}
@@ -0,0 +1,20 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/services"
+	"github.com/gobuffalo/buffalo"
+)
+
+// JWKS publishes the public keys tokens are signed with, so other tools can verify them.
+func JWKS(c buffalo.Context) error {
+	set, err := services.Keys.JWKS()
+	if err != nil {
+		fmt.Println(err)
+		return c.Render(http.StatusServiceUnavailable, r.JSON(map[string]string{"message": messages.TokenUnavailableError}))
+	}
+	return c.Render(http.StatusOK, r.JSON(set))
+}
@@ -0,0 +1,123 @@
+package actions
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dosaki/emote_combat_server/helpers"
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/envy"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// how long a write to a client may take
+	liveWriteWait = 10 * time.Second
+	// how long a client may stay silent before we give up on it; it must answer our pings
+	livePongWait   = 60 * time.Second
+	livePingPeriod = (livePongWait * 9) / 10
+	// clients have nothing to say besides pongs
+	liveMaxMessageSize = 512
+)
+
+// liveAllowedOrigins - the web pages that may subscribe to encounters from a browser, comma separated in LIVE_ALLOWED_ORIGINS
+var liveAllowedOrigins = strings.Split(envy.Get("LIVE_ALLOWED_ORIGINS", ""), ",")
+
+// liveOriginAllowed - whether a live subscription may come from where the request says it does.
+// Browsers always send an Origin when opening a WebSocket or an EventSource; the addon isn't a browser and sends none.
+func liveOriginAllowed(request *http.Request) bool {
+	origin := request.Header.Get("Origin")
+	if len(origin) == 0 {
+		return true
+	}
+	for _, allowed := range liveAllowedOrigins {
+		if allowed = strings.TrimSpace(allowed); len(allowed) > 0 && strings.EqualFold(origin, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     liveOriginAllowed,
+}
+
+// EncounterLive subscribes to an encounter over a WebSocket.
+// The client first gets the current state, then every event as it is logged, each followed by the new state.
+func EncounterLive(c buffalo.Context) error {
+	id, perr := helpers.Param(c, "id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoEncounterIDError}))
+	}
+
+	encounter, err := services.GetEncounter(models.DB, id)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	state, err := services.StateMessage(models.DB, encounter.ID)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		// the upgrader has already answered the request
+		return nil
+	}
+	defer conn.Close()
+
+	subscriber := services.Live.Subscribe(encounter.ID)
+	defer services.Live.Unsubscribe(subscriber)
+
+	// read until the client goes away so we notice it, and so pongs get handled
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		conn.SetReadLimit(liveMaxMessageSize)
+		conn.SetReadDeadline(time.Now().Add(livePongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(livePongWait))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	conn.SetWriteDeadline(time.Now().Add(liveWriteWait))
+	if err := conn.WriteJSON(state); err != nil {
+		return nil
+	}
+
+	ticker := time.NewTicker(livePingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case m, ok := <-subscriber.Messages:
+			conn.SetWriteDeadline(time.Now().Add(liveWriteWait))
+			if !ok {
+				// we were dropped for falling behind
+				conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return nil
+			}
+			if err := conn.WriteJSON(m); err != nil {
+				return nil
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(liveWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return nil
+			}
+		case <-done:
+			return nil
+		}
+	}
+}
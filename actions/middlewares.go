@@ -4,15 +4,31 @@ import (
 	"fmt"
 	"github.com/dosaki/emote_combat_server/messages"
 	"net/http"
+	"strings"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/dosaki/emote_combat_server/helpers"
+	"github.com/dosaki/emote_combat_server/models"
 	"github.com/dosaki/emote_combat_server/services"
 	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+	"github.com/gorilla/websocket"
 )
 
+func isLiveRequest(request *http.Request) bool {
+	return websocket.IsWebSocketUpgrade(request) || strings.Contains(request.Header.Get("Accept"), "text/event-stream")
+}
+
 func getToken(c buffalo.Context) (*jwt.Token, error) {
 	tokenString := c.Request().Header.Get("Authorization")
+	if len(tokenString) == 0 && isLiveRequest(c.Request()) {
+		// browsers can't set headers when opening a WebSocket or an EventSource, so only pages we trust may pass it here
+		if !liveOriginAllowed(c.Request()) {
+			return nil, c.Error(http.StatusForbidden, fmt.Errorf(messages.OriginNotAllowedError))
+		}
+		tokenString = c.Request().URL.Query().Get("token")
+	}
 	if len(tokenString) == 0 {
 		return nil, c.Error(http.StatusUnauthorized, fmt.Errorf(messages.NoTokenError))
 	}
@@ -25,6 +41,10 @@ func getToken(c buffalo.Context) (*jwt.Token, error) {
 	if err != nil {
 		return nil, c.Error(http.StatusUnauthorized, fmt.Errorf(messages.InvalidUserTokenError))
 	}
+	// access tokens of a login that has ended stop working straight away rather than when they expire
+	if services.LoginIsRevoked(services.TokenSession(token)) {
+		return nil, c.Error(http.StatusUnauthorized, fmt.Errorf(messages.InvalidTokenError))
+	}
 	return token, nil
 }
 
@@ -49,6 +69,14 @@ func checkClaims(c buffalo.Context, token *jwt.Token, checkUser bool) bool {
 	return false
 }
 
+// currentUserID - the ID of the user the request's token belongs to, or nil on unrestricted routes
+func currentUserID(c buffalo.Context) UUID.UUID {
+	if user, ok := c.Value("user").(models.User); ok {
+		return user.ID
+	}
+	return UUID.Nil
+}
+
 // PlayerRestrictedHandlerMiddleware - handles restricted actions by making sure they have a valid token and are acting on the correct player
 func PlayerRestrictedHandlerMiddleware(next buffalo.Handler) buffalo.Handler {
 	return func(c buffalo.Context) error {
@@ -80,3 +108,46 @@ func RestrictedHandlerMiddleware(next buffalo.Handler) buffalo.Handler {
 		return c.Error(http.StatusUnauthorized, fmt.Errorf(messages.InvalidTokenOrUnauthorizedError))
 	}
 }
+
+// RoleRestrictedHandlerMiddleware - like RestrictedHandlerMiddleware, but the user also has to have one of the given roles
+func RoleRestrictedHandlerMiddleware(roles ...string) buffalo.MiddlewareFunc {
+	return func(next buffalo.Handler) buffalo.Handler {
+		return func(c buffalo.Context) error {
+			token, buffaloErr := getToken(c)
+			if buffaloErr != nil {
+				return buffaloErr
+			}
+
+			if !checkClaims(c, token, false) {
+				return c.Error(http.StatusUnauthorized, fmt.Errorf(messages.InvalidTokenOrUnauthorizedError))
+			}
+			if user, ok := c.Value("user").(models.User); !ok || !user.HasRole(roles...) {
+				return c.Error(http.StatusForbidden, fmt.Errorf(messages.MissingRoleError))
+			}
+			return next(c)
+		}
+	}
+}
+
+// EventPublisherMiddleware - once a request's transaction is committed, pushes the encounter events it logged to live subscribers.
+// It has to sit outside popmw.Transaction so it runs after the commit.
+func EventPublisherMiddleware(next buffalo.Handler) buffalo.Handler {
+	return func(c buffalo.Context) error {
+		err := next(c)
+
+		tx, ok := c.Value("tx").(*pop.Connection)
+		if !ok {
+			return err
+		}
+		// Only errors are sure to roll popmw.Transaction back; whether other failed responses do depends on the buffalo-pop
+		// version. Their events are discarded either way: that's safe, because if they were committed after all they are
+		// still in the log, and subscribers catch up from there (EncounterLogList ?after=, Last-Event-ID on the stream).
+		// Handlers that must not leave half their changes behind return an error instead of rendering one
+		if res, ok := c.Response().(*buffalo.Response); err != nil || (ok && (res.Status < 200 || res.Status >= 400)) {
+			services.DiscardEvents(tx)
+			return err
+		}
+		services.PublishEvents(tx)
+		return err
+	}
+}
@@ -0,0 +1,76 @@
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
+	"github.com/dosaki/emote_combat_server/services/mail"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/pop"
+)
+
+func getPasswordForgotBody(c buffalo.Context) models.PasswordForgotJSON {
+	request := c.Request()
+	decoder := json.NewDecoder(request.Body)
+	body := models.PasswordForgotJSON{}
+	err := decoder.Decode(&body)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+func getPasswordResetBody(c buffalo.Context) models.PasswordResetJSON {
+	request := c.Request()
+	decoder := json.NewDecoder(request.Body)
+	body := models.PasswordResetJSON{}
+	err := decoder.Decode(&body)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+// ForgotPassword emails a player a single use token to choose a new password with.
+// It answers the same whether or not the email belongs to anyone, and whether or not the email could be sent.
+func ForgotPassword(c buffalo.Context) error {
+	body := getPasswordForgotBody(c)
+
+	// the reset is committed on its own so the token in the email is saved before anyone can use it
+	var message *mail.Message
+	err := models.DB.Transaction(func(tx *pop.Connection) error {
+		var rerr error
+		message, rerr = services.RequestPasswordReset(tx, body.Email)
+		return rerr
+	})
+	if err != nil {
+		fmt.Println("could not start a password reset", err)
+	} else if message != nil {
+		if err := services.Mailer.Send(*message); err != nil {
+			fmt.Println("could not send a password reset", err)
+		}
+	}
+	return c.Render(http.StatusOK, r.JSON(map[string]string{"message": messages.PasswordResetFeedback}))
+}
+
+// ResetPassword sets a new password using a token from ForgotPassword.
+func ResetPassword(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	body := getPasswordResetBody(c)
+	verrs, err := services.ResetPassword(tx, body.Token, body.Password, body.PasswordConfirmation)
+	if err != nil {
+		return c.Render(http.StatusBadRequest, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusBadRequest, r.JSON(verrs))
+	}
+	return c.Render(http.StatusOK, r.JSON(map[string]string{}))
+}
@@ -0,0 +1,89 @@
+package actions
+
+import (
+	"encoding/json"
+
+	"github.com/dosaki/emote_combat_server/helpers"
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+func getRollRequestBody(c buffalo.Context) models.RollRequestJSON {
+	request := c.Request()
+	decoder := json.NewDecoder(request.Body)
+	body := models.RollRequestJSON{}
+	err := decoder.Decode(&body)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+// RollCreate rolls dice for a character against one of their skills.
+func RollCreate(c buffalo.Context) error {
+	playerID, pierr := helpers.Param(c, "player_id")
+	if pierr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoPlayerIDError}))
+	}
+
+	characterID, cierr := helpers.Param(c, "character_id")
+	if cierr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoCharacterIDError}))
+	}
+
+	characterUUID, cuuiderr := UUID.FromString(characterID)
+	if cuuiderr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.BadUUIDError}))
+	}
+
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	var characters []models.Character
+	cerr := tx.Where("player_id = ?", playerID).Where("id = ?", characterID).All(&characters)
+	if cerr != nil || len(characters) == 0 {
+		return c.Render(404, r.JSON(map[string]string{"message": messages.PlayerCharacterNotFoundError}))
+	}
+
+	body := getRollRequestBody(c)
+	skill, serr := services.FindSkill(tx, characters[0].SkillSetID, body.SkillID, body.Skill)
+	if serr != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": serr.Error()}))
+	}
+
+	roll, err := services.RollSkill(tx, characterUUID, skill, body.Expression)
+	if err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(201, r.JSON(roll))
+}
+
+// RollList lists a character's rolls, newest first.
+func RollList(c buffalo.Context) error {
+	characterID, cierr := helpers.Param(c, "character_id")
+	if cierr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoCharacterIDError}))
+	}
+
+	playerID, pierr := helpers.Param(c, "player_id")
+	if pierr == nil {
+		var characters []models.Character
+		cerr := models.DB.Where("player_id = ?", playerID).Where("id = ?", characterID).All(&characters)
+		if cerr != nil || len(characters) == 0 {
+			return c.Render(404, r.JSON(map[string]string{"message": messages.PlayerCharacterNotFoundError}))
+		}
+	}
+
+	rolls := []models.Roll{}
+	err := models.DB.Where("character_id = ?", characterID).Order("created_at desc").All(&rolls)
+	if err == nil {
+		return c.Render(200, r.JSON(rolls))
+	}
+	return c.Render(500, r.JSON(map[string]string{"message": messages.ProblemGettingRollsError}))
+}
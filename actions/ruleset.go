@@ -0,0 +1,11 @@
+package actions
+
+import (
+	"github.com/dosaki/emote_combat_server/services/ruleset"
+	"github.com/gobuffalo/buffalo"
+)
+
+// RulesetList lists the names of the rulesets an encounter can be created with.
+func RulesetList(c buffalo.Context) error {
+	return c.Render(200, r.JSON(ruleset.Names()))
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/dosaki/emote_combat_server/helpers"
 	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
 	"github.com/gobuffalo/buffalo"
 	"github.com/gobuffalo/pop"
 	UUID "github.com/gobuffalo/uuid"
@@ -34,12 +35,7 @@ func getSheetEntriesBody(c buffalo.Context) []models.CharacterSheetEntry {
 	return body
 }
 
-func createOne(c buffalo.Context, body models.CharacterSheetEntry, characterID string) (models.CharacterSheetEntry, error) {
-	tx, ok := c.Value("tx").(*pop.Connection)
-	if !ok {
-		panic(messages.NoConnectionError)
-	}
-
+func newSheetEntry(body models.CharacterSheetEntry, characterID string) (models.CharacterSheetEntry, error) {
 	characterUUID, puuiderr := UUID.FromString(characterID)
 	if puuiderr != nil {
 		return models.CharacterSheetEntry{}, errors.New(messages.BadUUIDError)
@@ -50,26 +46,17 @@ func createOne(c buffalo.Context, body models.CharacterSheetEntry, characterID s
 	sheetEntry.SkillID = body.SkillID
 	sheetEntry.Value = body.Value
 	sheetEntry.Note = body.Note
-
-	if tx.Create(&sheetEntry) == nil {
-		return sheetEntry, nil
-	}
-	return models.CharacterSheetEntry{}, errors.New(messages.UnknownError)
+	return sheetEntry, nil
 }
 
-func updateOne(c buffalo.Context, body models.CharacterSheetEntry, characterID string, uuid string) (models.CharacterSheetEntry, error) {
-	tx, ok := c.Value("tx").(*pop.Connection)
-	if !ok {
-		panic(messages.NoConnectionError)
-	}
-
+func changedSheetEntry(tx *pop.Connection, body models.CharacterSheetEntry, characterID string, uuid string) (models.CharacterSheetEntry, error) {
 	characterUUID, puuiderr := UUID.FromString(characterID)
 	if puuiderr != nil {
 		return models.CharacterSheetEntry{}, errors.New(messages.BadUUIDError)
 	}
 
 	var sheetEntries []models.CharacterSheetEntry
-	err := models.DB.Where("character_id = ?", characterID).Where("id = ?", uuid).All(&sheetEntries)
+	err := tx.Where("character_id = ?", characterID).Where("id = ?", uuid).All(&sheetEntries)
 	if err != nil {
 		return models.CharacterSheetEntry{}, errors.New(messages.ProblemGettingSheetEntryError)
 	}
@@ -82,11 +69,24 @@ func updateOne(c buffalo.Context, body models.CharacterSheetEntry, characterID s
 	sheetEntry.SkillID = body.SkillID
 	sheetEntry.Value = body.Value
 	sheetEntry.Note = body.Note
+	return sheetEntry, nil
+}
 
-	if tx.Save(&sheetEntry) == nil {
-		return sheetEntry, nil
+// saveSheetEntries - saves new or changed entries together, as long as the sheet they make up is valid
+func saveSheetEntries(c buffalo.Context, character models.Character, sheetEntries []models.CharacterSheetEntry) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
 	}
-	return sheetEntry, errors.New(messages.UnknownError)
+
+	verrs, err := services.SaveSheetEntries(tx, character, sheetEntries, currentUserID(c))
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	if verrs.HasAny() {
+		return c.Render(400, r.JSON(verrs))
+	}
+	return nil
 }
 
 // SheetEntryCreate default implementation.
@@ -108,11 +108,16 @@ func SheetEntryCreate(c buffalo.Context) error {
 	}
 
 	body := getSheetEntryBody(c)
-	sheetEntry, err := createOne(c, body, characterID)
-	if err == nil {
-		return c.Render(201, r.JSON(sheetEntry))
+	sheetEntry, err := newSheetEntry(body, characterID)
+	if err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
 	}
-	return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+
+	sheetEntries := []models.CharacterSheetEntry{sheetEntry}
+	if serr := saveSheetEntries(c, characters[0], sheetEntries); serr != nil {
+		return serr
+	}
+	return c.Render(201, r.JSON(sheetEntries[0]))
 }
 
 // SheetEntriesCreate default implementation.
@@ -136,12 +141,16 @@ func SheetEntriesCreate(c buffalo.Context) error {
 	bodies := getSheetEntriesBody(c)
 	var sheetEntries []models.CharacterSheetEntry
 	for _, body := range bodies {
-		sheetEntry, err := createOne(c, body, characterID)
+		sheetEntry, err := newSheetEntry(body, characterID)
 		if err != nil {
 			return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
 		}
 		sheetEntries = append(sheetEntries, sheetEntry)
 	}
+
+	if serr := saveSheetEntries(c, characters[0], sheetEntries); serr != nil {
+		return serr
+	}
 	return c.Render(200, r.JSON(sheetEntries))
 }
 
@@ -168,13 +177,21 @@ func SheetEntryUpdate(c buffalo.Context) error {
 		return c.Render(400, r.JSON(map[string]string{"message": messages.NoSheetIDError}))
 	}
 
-	sheetEntry, seError := updateOne(c, getSheetEntryBody(c), characterID, uuid)
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
 
-	if seError == nil {
-		return c.Render(200, r.JSON(sheetEntry))
+	sheetEntry, seError := changedSheetEntry(tx, getSheetEntryBody(c), characterID, uuid)
+	if seError != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": seError.Error()}))
 	}
 
-	return c.Render(500, r.JSON(map[string]string{"message": messages.UnknownError}))
+	sheetEntries := []models.CharacterSheetEntry{sheetEntry}
+	if serr := saveSheetEntries(c, characters[0], sheetEntries); serr != nil {
+		return serr
+	}
+	return c.Render(200, r.JSON(sheetEntries[0]))
 }
 
 // SheetEntriesUpdate default implementation.
@@ -195,15 +212,24 @@ func SheetEntriesUpdate(c buffalo.Context) error {
 		return c.Render(404, r.JSON(map[string]string{"message": messages.PlayerCharacterNotFoundError}))
 	}
 
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
 	bodies := getSheetEntriesBody(c)
 	var sheetEntries []models.CharacterSheetEntry
 	for _, body := range bodies {
-		sheetEntry, err := updateOne(c, body, characterID, body.ID.String())
+		sheetEntry, err := changedSheetEntry(tx, body, characterID, body.ID.String())
 		if err != nil {
 			return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
 		}
 		sheetEntries = append(sheetEntries, sheetEntry)
 	}
+
+	if serr := saveSheetEntries(c, characters[0], sheetEntries); serr != nil {
+		return serr
+	}
 	return c.Render(200, r.JSON(sheetEntries))
 }
 
@@ -244,13 +270,10 @@ func SheetEntryDelete(c buffalo.Context) error {
 		panic(messages.NoConnectionError)
 	}
 
-	sheetEntry := sheetEntries[0]
-
-	if tx.Destroy(&sheetEntry) == nil {
-		return c.Render(201, r.JSON(map[string]string{}))
+	if err := services.DeleteSheetEntry(tx, sheetEntries[0], currentUserID(c)); err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
 	}
-
-	return c.Render(500, r.JSON(map[string]string{"message": messages.UnknownError}))
+	return c.Render(201, r.JSON(map[string]string{}))
 }
 
 // SheetEntryList default implementation.
@@ -269,26 +292,39 @@ func SheetEntryList(c buffalo.Context) error {
 		}
 	}
 
-	var query *pop.Query
-	var err error
-	var sheetEntries []models.CharacterSheetEntry
-	query = models.DB.Where("character_id = ?", characterID)
+	characterUUID, puuiderr := UUID.FromString(characterID)
+	if puuiderr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.BadUUIDError}))
+	}
 
-	uuid, perr := helpers.Param(c, "id")
-	if perr != nil {
-		err = query.All(&sheetEntries)
-		if err == nil {
-			return c.Render(200, r.JSON(sheetEntries))
-		}
-	} else {
-		err = query.Where("id = ?", uuid).All(&sheetEntries)
-		if len(sheetEntries) == 0 {
-			return c.Render(404, r.JSON(map[string]string{"message": messages.SheetNotFoundError}))
+	// ?missing=true lists the skills the character has no entry for instead
+	if missing, merr := helpers.Param(c, "missing"); merr == nil && missing == "true" {
+		character, err := services.GetCharacter(models.DB, characterUUID)
+		if err != nil {
+			return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
 		}
-		if err == nil {
-			return c.Render(200, r.JSON(sheetEntries[0]))
+		skills, err := services.MissingSkills(models.DB, character)
+		if err != nil {
+			return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
 		}
+		return c.Render(200, r.JSON(skills))
+	}
+
+	// skills worked out from their subskills are always shown with their current value,
+	// and every entry says which prerequisites it doesn't meet yet
+	sheetEntries, err := services.GetSheetView(models.DB, characterUUID)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
 	}
 
-	return c.Render(500, r.JSON(map[string]string{"message": messages.ProblemGettingSheetEntryError}))
+	uuid, perr := helpers.Param(c, "id")
+	if perr != nil {
+		return c.Render(200, r.JSON(sheetEntries))
+	}
+	for _, sheetEntry := range sheetEntries {
+		if sheetEntry.ID.String() == uuid {
+			return c.Render(200, r.JSON(sheetEntry))
+		}
+	}
+	return c.Render(404, r.JSON(map[string]string{"message": messages.SheetNotFoundError}))
 }
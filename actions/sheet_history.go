@@ -0,0 +1,68 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dosaki/emote_combat_server/helpers"
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
+	"github.com/gobuffalo/buffalo"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+// getPathCharacter - loads the character in the URL
+func getPathCharacter(c buffalo.Context) (models.Character, error) {
+	characterID, cierr := helpers.Param(c, "character_id")
+	if cierr != nil {
+		return models.Character{}, c.Error(http.StatusBadRequest, fmt.Errorf(messages.NoCharacterIDError))
+	}
+	characterUUID, err := UUID.FromString(characterID)
+	if err != nil {
+		return models.Character{}, c.Error(http.StatusBadRequest, fmt.Errorf(messages.BadUUIDError))
+	}
+	character, err := services.GetCharacter(models.DB, characterUUID)
+	if err != nil {
+		return models.Character{}, c.Error(http.StatusNotFound, err)
+	}
+	return character, nil
+}
+
+// SheetHistoryList lists every change made to a character's sheet, newest first.
+func SheetHistoryList(c buffalo.Context) error {
+	character, rerr := getPathCharacter(c)
+	if rerr != nil {
+		return rerr
+	}
+
+	changes, err := services.GetSheetHistory(models.DB, character.ID)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(changes))
+}
+
+// SheetAsOf reads a character's whole sheet as it was at the time given in `?at=`.
+func SheetAsOf(c buffalo.Context) error {
+	character, rerr := getPathCharacter(c)
+	if rerr != nil {
+		return rerr
+	}
+
+	param, aerr := helpers.Param(c, "at")
+	if aerr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.BadTimestampError}))
+	}
+	at, err := time.Parse(time.RFC3339, param)
+	if err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.BadTimestampError}))
+	}
+
+	sheet, err := services.GetSheetAt(models.DB, character.ID, at)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(sheet))
+}
@@ -0,0 +1,148 @@
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/dosaki/emote_combat_server/helpers"
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/pop"
+)
+
+func getSnapshotBody(c buffalo.Context) models.SheetSnapshot {
+	request := c.Request()
+	decoder := json.NewDecoder(request.Body)
+	body := models.SheetSnapshot{}
+	err := decoder.Decode(&body)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+// getPlayerCharacter - loads the character in the URL, making sure it belongs to the player in the URL
+func getPlayerCharacter(c buffalo.Context, tx *pop.Connection) (models.Character, error) {
+	playerID, pierr := helpers.Param(c, "player_id")
+	if pierr != nil {
+		return models.Character{}, c.Error(http.StatusBadRequest, fmt.Errorf(messages.NoPlayerIDError))
+	}
+	characterID, cierr := helpers.Param(c, "character_id")
+	if cierr != nil {
+		return models.Character{}, c.Error(http.StatusBadRequest, fmt.Errorf(messages.NoCharacterIDError))
+	}
+
+	var characters []models.Character
+	err := tx.Where("player_id = ?", playerID).Where("id = ?", characterID).All(&characters)
+	if err != nil || len(characters) == 0 {
+		return models.Character{}, c.Error(http.StatusNotFound, fmt.Errorf(messages.PlayerCharacterNotFoundError))
+	}
+	return characters[0], nil
+}
+
+// getPathSnapshot - loads the snapshot in the URL
+func getPathSnapshot(c buffalo.Context, tx *pop.Connection, character models.Character) (models.SheetSnapshot, error) {
+	id, perr := helpers.Param(c, "id")
+	if perr != nil {
+		return models.SheetSnapshot{}, c.Error(http.StatusBadRequest, fmt.Errorf(messages.NoSnapshotIDError))
+	}
+	snapshot, err := services.GetSnapshot(tx, character.ID, id)
+	if err != nil {
+		return models.SheetSnapshot{}, c.Error(http.StatusNotFound, err)
+	}
+	return snapshot, nil
+}
+
+// SheetSnapshotList lists a character's snapshots or reads a single one.
+func SheetSnapshotList(c buffalo.Context) error {
+	character, rerr := getPathCharacter(c)
+	if rerr != nil {
+		return rerr
+	}
+
+	if _, perr := helpers.Param(c, "id"); perr == nil {
+		snapshot, serr := getPathSnapshot(c, models.DB, character)
+		if serr != nil {
+			return serr
+		}
+		return c.Render(200, r.JSON(snapshot))
+	}
+
+	snapshots, err := services.GetSnapshots(models.DB, character.ID)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(snapshots))
+}
+
+// SheetSnapshotDiff lists how a snapshot differs from the character's current sheet.
+func SheetSnapshotDiff(c buffalo.Context) error {
+	character, rerr := getPathCharacter(c)
+	if rerr != nil {
+		return rerr
+	}
+	snapshot, serr := getPathSnapshot(c, models.DB, character)
+	if serr != nil {
+		return serr
+	}
+
+	differences, err := services.DiffSnapshot(models.DB, snapshot)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(differences))
+}
+
+// SheetSnapshotCreate takes a named snapshot of a character's current sheet.
+func SheetSnapshotCreate(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	character, rerr := getPlayerCharacter(c, tx)
+	if rerr != nil {
+		return rerr
+	}
+
+	body := getSnapshotBody(c)
+	snapshot, verrs, err := services.CreateSnapshot(tx, character, body.Name, currentUserID(c))
+	if err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	if verrs.HasAny() {
+		return c.Render(400, r.JSON(verrs))
+	}
+	return c.Render(201, r.JSON(snapshot))
+}
+
+// SheetSnapshotRestore rewrites a character's whole sheet to match a snapshot, all at once or not at all.
+func SheetSnapshotRestore(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	character, rerr := getPlayerCharacter(c, tx)
+	if rerr != nil {
+		return rerr
+	}
+	snapshot, serr := getPathSnapshot(c, tx, character)
+	if serr != nil {
+		return serr
+	}
+
+	// the restore deletes entries before it saves the rest, so failures are returned as errors
+	// to make sure the request's transaction is rolled back, deletions included
+	sheet, verrs, err := services.RestoreSnapshot(tx, character, snapshot, currentUserID(c))
+	if err != nil {
+		return c.Error(http.StatusInternalServerError, err)
+	}
+	if verrs.HasAny() {
+		return c.Error(http.StatusBadRequest, verrs)
+	}
+	return c.Render(200, r.JSON(sheet))
+}
@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 
 	"github.com/dosaki/emote_combat_server/helpers"
+	"github.com/dosaki/emote_combat_server/messages"
 	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
 	"github.com/gobuffalo/buffalo"
 	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
 )
 
 func getSkillBody(c buffalo.Context) models.Skill {
@@ -34,8 +37,29 @@ func SkillCreate(c buffalo.Context) error {
 	skill.Description = body.Description
 	skill.ParentSkillID = body.ParentSkillID
 	skill.Cost = body.Cost
+	skill.StartingValue = body.StartingValue
+	skill.MinValue = body.MinValue
+	skill.MaxValue = body.MaxValue
+	skill.ChildRule = body.ChildRule
+	skill.SkillSetID = body.SkillSetID
+	if skill.SkillSetID == UUID.Nil {
+		skill.SkillSetID = models.DefaultSkillSetID
+	}
+
+	verrs := skill.CheckBounds()
+	verrs.Append(skill.CheckChildRule())
+	if verrs.HasAny() {
+		return c.Render(400, r.JSON(verrs))
+	}
+	if err := services.CheckSkillPlacement(tx, skill); err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
 
 	if tx.Create(&skill) == nil {
+		// characters created before the skill existed get an entry for it too
+		if _, err := services.BackfillSkill(tx, skill); err != nil {
+			return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+		}
 		return c.Render(201, r.JSON(skill))
 	}
 	return c.Render(400, r.JSON(map[string]string{}))
@@ -63,11 +87,43 @@ func SkillUpdate(c buffalo.Context) error {
 	}
 
 	body := getSkillBody(c)
+	cycle, err := services.CreatesCycle(tx, skills[0].ID, body.ParentSkillID)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	if cycle {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.ParentCycleError}))
+	}
+
 	skill := skills[0]
 	skill.Name = body.Name
 	skill.Description = body.Description
 	skill.ParentSkillID = body.ParentSkillID
 	skill.Cost = body.Cost
+	skill.StartingValue = body.StartingValue
+	skill.MinValue = body.MinValue
+	skill.MaxValue = body.MaxValue
+	skill.ChildRule = body.ChildRule
+
+	verrs := skill.CheckBounds()
+	verrs.Append(skill.CheckChildRule())
+	if verrs.HasAny() {
+		return c.Render(400, r.JSON(verrs))
+	}
+	// a skill stays in the skill set it was created in
+	if err := services.CheckSkillPlacement(tx, skill); err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	// a new rule can't leave sheets that already break it
+	if skill.ChildRule != skills[0].ChildRule {
+		rverrs, err := services.CheckChildRuleChange(tx, skill)
+		if err != nil {
+			return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+		}
+		if rverrs.HasAny() {
+			return c.Render(409, r.JSON(rverrs))
+		}
+	}
 
 	if tx.Save(&skill) == nil {
 		return c.Render(200, r.JSON(skill))
@@ -76,7 +132,8 @@ func SkillUpdate(c buffalo.Context) error {
 	return c.Render(500, r.JSON(map[string]string{"message": "Unknown error."}))
 }
 
-// SkillDelete default implementation.
+// SkillDelete deletes a skill. The mode parameter says what happens to whatever uses it:
+// refuse (the default) won't delete a skill in use, cascade deletes its subskills too and reparent moves them up a level.
 func SkillDelete(c buffalo.Context) error {
 	uuid, perr := helpers.Param(c, "id")
 	if perr != nil {
@@ -97,13 +154,56 @@ func SkillDelete(c buffalo.Context) error {
 		panic("Unable to get connection")
 	}
 
-	skill := skills[0]
+	mode, merr := helpers.Param(c, "mode")
+	if merr != nil {
+		mode = services.SkillDeleteRefuse
+	}
 
-	if tx.Destroy(&skill) == nil {
-		return c.Render(201, r.JSON(map[string]string{}))
+	deletion, err := services.DeleteSkill(tx, skills[0], mode, currentUserID(c))
+	if err != nil {
+		if mode == services.SkillDeleteRefuse && deletion.Usage.InUse() {
+			return c.Render(409, r.JSON(map[string]interface{}{"message": err.Error(), "usage": deletion.Usage}))
+		}
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
 	}
+	return c.Render(200, r.JSON(deletion))
+}
 
-	return c.Render(500, r.JSON(map[string]string{"message": "Unknown error."}))
+// SkillTree returns every skill (or those of one skill set) nested under its parent,
+// optionally with a character's sheet values inline.
+func SkillTree(c buffalo.Context) error {
+	skillSetUUID := UUID.Nil
+	if skillSetID, sserr := helpers.Param(c, "skill_set_id"); sserr == nil {
+		var err error
+		if skillSetUUID, err = UUID.FromString(skillSetID); err != nil {
+			return c.Render(400, r.JSON(map[string]string{"message": messages.BadUUIDError}))
+		}
+	}
+
+	characterUUID := UUID.Nil
+	characterID, cierr := helpers.Param(c, "character_id")
+	if cierr == nil {
+		var err error
+		characterUUID, err = UUID.FromString(characterID)
+		if err != nil {
+			return c.Render(400, r.JSON(map[string]string{"message": messages.BadUUIDError}))
+		}
+		character, err := services.GetCharacter(models.DB, characterUUID)
+		if err != nil {
+			return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+		}
+		// a character's sheet only has the skills of their own set
+		skillSetUUID = character.SkillSetID
+	}
+
+	tree, err := services.GetSkillTree(models.DB, skillSetUUID, characterUUID)
+	if cycle, ok := err.(*services.SkillCycle); ok {
+		return c.Render(409, r.JSON(map[string]interface{}{"message": cycle.Error(), "skills": cycle.Skills}))
+	}
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(tree))
 }
 
 // SkillList default implementation.
@@ -119,6 +219,11 @@ func SkillList(c buffalo.Context) error {
 		query = models.DB.Where("parent_skill_id = ?", parentID)
 	}
 
+	skillSetID, sserr := helpers.Param(c, "skill_set_id")
+	if sserr == nil {
+		query = query.Where("skill_set_id = ?", skillSetID)
+	}
+
 	uuid, perr := helpers.Param(c, "id")
 
 	if perr != nil {
@@ -0,0 +1,102 @@
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/dosaki/emote_combat_server/helpers"
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+func getPrerequisiteBody(c buffalo.Context) models.SkillPrerequisite {
+	request := c.Request()
+	decoder := json.NewDecoder(request.Body)
+	body := models.SkillPrerequisite{}
+	err := decoder.Decode(&body)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+// getPathSkill - loads the skill in the URL
+func getPathSkill(c buffalo.Context, tx *pop.Connection) (models.Skill, error) {
+	id, perr := helpers.Param(c, "id")
+	if perr != nil {
+		return models.Skill{}, c.Error(http.StatusBadRequest, fmt.Errorf(messages.NoSkillError))
+	}
+	skillID, err := UUID.FromString(id)
+	if err != nil {
+		return models.Skill{}, c.Error(http.StatusBadRequest, fmt.Errorf(messages.BadUUIDError))
+	}
+	skill, err := services.FindSkill(tx, UUID.Nil, skillID, "")
+	if err != nil {
+		return models.Skill{}, c.Error(http.StatusNotFound, err)
+	}
+	return skill, nil
+}
+
+// SkillPrerequisiteList lists what a skill requires.
+func SkillPrerequisiteList(c buffalo.Context) error {
+	skill, serr := getPathSkill(c, models.DB)
+	if serr != nil {
+		return serr
+	}
+
+	prerequisites, err := services.GetSkillPrerequisites(models.DB, skill.ID)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(prerequisites))
+}
+
+// SkillPrerequisiteCreate makes a skill require another one to be at a minimum value.
+func SkillPrerequisiteCreate(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	skill, serr := getPathSkill(c, tx)
+	if serr != nil {
+		return serr
+	}
+
+	prerequisite, verrs, err := services.AddPrerequisite(tx, skill, getPrerequisiteBody(c))
+	if err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	if verrs.HasAny() {
+		return c.Render(400, r.JSON(verrs))
+	}
+	return c.Render(201, r.JSON(prerequisite))
+}
+
+// SkillPrerequisiteDelete drops one of a skill's prerequisites.
+func SkillPrerequisiteDelete(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	skill, serr := getPathSkill(c, tx)
+	if serr != nil {
+		return serr
+	}
+
+	prerequisiteID, perr := helpers.Param(c, "prerequisite_id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoPrerequisiteIDError}))
+	}
+
+	if err := services.RemovePrerequisite(tx, skill.ID, prerequisiteID); err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(map[string]string{}))
+}
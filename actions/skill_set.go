@@ -0,0 +1,102 @@
+package actions
+
+import (
+	"encoding/json"
+
+	"github.com/dosaki/emote_combat_server/helpers"
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+func getSkillSetBody(c buffalo.Context) models.SkillSet {
+	request := c.Request()
+	decoder := json.NewDecoder(request.Body)
+	body := models.SkillSet{}
+	err := decoder.Decode(&body)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+// SkillSetCreate adds a new, empty skill set.
+func SkillSetCreate(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	body := getSkillSetBody(c)
+	skillSet := models.SkillSet{}
+	skillSet.Name = body.Name
+	skillSet.Description = body.Description
+
+	verrs, err := tx.ValidateAndCreate(&skillSet)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": messages.UnknownError}))
+	}
+	if verrs.HasAny() {
+		return c.Render(400, r.JSON(verrs))
+	}
+	return c.Render(201, r.JSON(skillSet))
+}
+
+// SkillSetUpdate renames or redescribes a skill set.
+func SkillSetUpdate(c buffalo.Context) error {
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	id, perr := helpers.Param(c, "id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoSkillSetIDError}))
+	}
+	skillSetID, err := UUID.FromString(id)
+	if err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.BadUUIDError}))
+	}
+	skillSet, err := services.GetSkillSet(tx, skillSetID)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	body := getSkillSetBody(c)
+	skillSet.Name = body.Name
+	skillSet.Description = body.Description
+
+	verrs, err := tx.ValidateAndSave(&skillSet)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": messages.UnknownError}))
+	}
+	if verrs.HasAny() {
+		return c.Render(400, r.JSON(verrs))
+	}
+	return c.Render(200, r.JSON(skillSet))
+}
+
+// SkillSetList lists all skill sets or reads a single one.
+func SkillSetList(c buffalo.Context) error {
+	id, perr := helpers.Param(c, "id")
+	if perr == nil {
+		skillSetID, err := UUID.FromString(id)
+		if err != nil {
+			return c.Render(400, r.JSON(map[string]string{"message": messages.BadUUIDError}))
+		}
+		skillSet, err := services.GetSkillSet(models.DB, skillSetID)
+		if err != nil {
+			return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+		}
+		return c.Render(200, r.JSON(skillSet))
+	}
+
+	skillSets := []models.SkillSet{}
+	if err := models.DB.Order("name asc").All(&skillSets); err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": messages.ProblemGettingSkillSetsError}))
+	}
+	return c.Render(200, r.JSON(skillSets))
+}
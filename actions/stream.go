@@ -0,0 +1,137 @@
+package actions
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dosaki/emote_combat_server/helpers"
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
+	"github.com/dosaki/emote_combat_server/services/hub"
+	"github.com/gobuffalo/buffalo"
+)
+
+// how often an idle stream sends a comment so proxies don't drop it
+const streamHeartbeat = 15 * time.Second
+
+// how long a client should wait before reconnecting, in milliseconds
+const streamRetry = 3000
+
+// writeStreamMessage - writes one Server-Sent Event; only logged events get an ID, so resuming picks up from the log
+func writeStreamMessage(w io.Writer, m hub.Message) error {
+	if m.ID > 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", m.ID); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", m.Type, m.Data)
+	return err
+}
+
+// lastEventID - where a reconnecting client left off, from the Last-Event-ID header or the last_event_id parameter
+func lastEventID(c buffalo.Context) (int, error) {
+	last := c.Request().Header.Get("Last-Event-ID")
+	if len(last) == 0 {
+		last, _ = helpers.Param(c, "last_event_id")
+	}
+	if len(last) == 0 {
+		return 0, nil
+	}
+	return strconv.Atoi(last)
+}
+
+// EncounterStream subscribes to an encounter with Server-Sent Events, for clients that can't use WebSockets.
+// It sends the same messages as EncounterLive. A client resuming with Last-Event-ID first gets every event it missed from the log.
+func EncounterStream(c buffalo.Context) error {
+	id, perr := helpers.Param(c, "id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoEncounterIDError}))
+	}
+
+	encounter, err := services.GetEncounter(models.DB, id)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	last, err := lastEventID(c)
+	if err != nil || last < 0 {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.BadLastEventIDError}))
+	}
+
+	w := c.Response()
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return c.Render(500, r.JSON(map[string]string{"message": messages.StreamingUnsupportedError}))
+	}
+
+	// subscribe before reading the log so nothing logged in between is lost
+	subscriber := services.Live.Subscribe(encounter.ID)
+	defer services.Live.Unsubscribe(subscriber)
+
+	missed, err := services.GetEvents(models.DB, encounter.ID, last)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	state, err := services.StateMessage(models.DB, encounter.ID)
+	if err != nil {
+		return c.Render(500, r.JSON(map[string]string{"message": err.Error()}))
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// stop nginx from buffering the stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", streamRetry); err != nil {
+		return nil
+	}
+	for _, event := range missed {
+		m, err := hub.NewMessage(event.Sequence, "event", event)
+		if err != nil {
+			return nil
+		}
+		if err := writeStreamMessage(w, m); err != nil {
+			return nil
+		}
+		last = event.Sequence
+	}
+	if err := writeStreamMessage(w, state); err != nil {
+		return nil
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case m, ok := <-subscriber.Messages:
+			if !ok {
+				// we were dropped for falling behind; the client reconnects and catches up from the log
+				return nil
+			}
+			if m.ID > 0 && m.ID <= last {
+				// already sent from the log
+				continue
+			}
+			if err := writeStreamMessage(w, m); err != nil {
+				return nil
+			}
+			if m.ID > 0 {
+				last = m.ID
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		case <-c.Request().Context().Done():
+			return nil
+		}
+		flusher.Flush()
+	}
+}
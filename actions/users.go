@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/dosaki/emote_combat_server/helpers"
+	"github.com/dosaki/emote_combat_server/messages"
 	"github.com/dosaki/emote_combat_server/models"
 	"github.com/dosaki/emote_combat_server/services"
 	"github.com/gobuffalo/buffalo"
@@ -108,3 +109,27 @@ func UserList(c buffalo.Context) error {
 
 	return c.Render(500, r.JSON(map[string]string{"message": "Problem getting player(s)."}))
 }
+
+// UserRoleUpdate makes a player a game master, an admin or a player again.
+func UserRoleUpdate(c buffalo.Context) error {
+	uuid, perr := helpers.Param(c, "player_id")
+	if perr != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": messages.NoPlayerIDError}))
+	}
+
+	user, err := services.GetUserByUUID(uuid)
+	if err != nil {
+		return c.Render(404, r.JSON(map[string]string{"message": "Player not found."}))
+	}
+
+	tx, ok := c.Value("tx").(*pop.Connection)
+	if !ok {
+		panic(messages.NoConnectionError)
+	}
+
+	body := getUserBody(c)
+	if err := services.SetUserRole(tx, &user, body.Role); err != nil {
+		return c.Render(400, r.JSON(map[string]string{"message": err.Error()}))
+	}
+	return c.Render(200, r.JSON(user))
+}
@@ -1 +1,5 @@
 module github.com/dosaki/emote_combat_server
+
+go 1.27.1
+
+require github.com/dgrijalva/jwt-go v3.2.0+incompatible
@@ -0,0 +1,28 @@
+package grifts
+
+import (
+	"fmt"
+
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
+	"github.com/gobuffalo/pop"
+	"github.com/markbates/grift/grift"
+)
+
+var _ = grift.Namespace("skills", func() {
+
+	grift.Desc("backfill", "Gives every character a sheet entry, at its starting value, for each skill they don't have yet")
+	grift.Add("backfill", func(c *grift.Context) error {
+		return models.DB.Transaction(func(tx *pop.Connection) error {
+			created, err := services.BackfillSkills(tx)
+			if err != nil {
+				return err
+			}
+			for name, count := range created {
+				fmt.Printf("%s: %d sheet entries created\n", name, count)
+			}
+			return nil
+		})
+	})
+
+})
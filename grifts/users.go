@@ -0,0 +1,33 @@
+package grifts
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services"
+	"github.com/markbates/grift/grift"
+)
+
+var _ = grift.Namespace("users", func() {
+
+	grift.Desc("role", "Gives a user a role, e.g. to make the first admin: buffalo task users:role someone@example.com admin")
+	grift.Add("role", func(c *grift.Context) error {
+		if len(c.Args) != 2 {
+			return errors.New("usage: users:role <email> <player|game_master|admin>")
+		}
+
+		var users []models.User
+		err := models.DB.Where("email = ?", strings.ToLower(strings.TrimSpace(c.Args[0]))).All(&users)
+		if err != nil || len(users) == 0 {
+			return errors.New("user not found")
+		}
+		if err := services.SetUserRole(models.DB, &users[0], c.Args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("%s is now %s\n", users[0].Email, users[0].Role)
+		return nil
+	})
+
+})
@@ -12,9 +12,98 @@ var NoSheetIDError = "no sheet ID provided"
 
 var PlayerCharacterNotFoundError = "unable to find that player's character"
 
+var SheetEntrySkillNotFoundError = "skill %s does not exist"
+var OverBudgetError = "sheet would spend %d points out of a budget of %d"
+var EntryCostError = "%s at %d costs %d points (starting value %d, cost %d per point)"
+var ImmutableSheetHistoryError = "sheet history cannot be changed"
+var ProblemGettingSheetHistoryError = "problem getting sheet history"
+var BadTimestampError = "timestamp must look like 2006-01-02T15:04:05Z07:00"
+var NoSnapshotIDError = "no snapshot ID provided"
+var SnapshotNotFoundError = "snapshot not found"
+var DuplicateSnapshotError = "character already has a snapshot with that name"
+var ProblemGettingSnapshotsError = "problem getting snapshot(s)"
+
+var NoSkillError = "no skill provided"
+var SkillNotFoundError = "skill not found"
+var ProblemGettingSkillsError = "problem getting skills"
+var MinAboveMaxError = "minimum value can't be above the maximum value"
+var StartingValueOutOfBoundsError = "starting value must be %s"
+var OutOfBoundsError = "%s can't be %d, it must be %s"
+var UnknownChildRuleError = "unknown child rule %q"
+var AboveParentError = "%s can't be %d, it can't go above %s (%d)"
+var SheetBreaksChildRuleError = "character %s: %s"
+var SkillCycleError = "skills %s are their own ancestors"
+var ParentCycleError = "a skill can't be a subskill of itself or of one of its subskills"
+var SkillInUseError = "skill is in use"
+
+var NoSkillSetIDError = "no skill set ID provided"
+var SkillSetNotFoundError = "skill set not found"
+var ProblemGettingSkillSetsError = "problem getting skill set(s)"
+var ParentInOtherSkillSetError = "a skill's parent has to be in the same skill set"
+var PrerequisiteInOtherSkillSetError = "a skill can only require skills from its own skill set"
+var SkillNotInSkillSetError = "%s isn't part of this character's skill set"
+var UnknownSkillDeleteModeError = "unknown delete mode, use refuse, cascade or reparent"
+
+var NoPrerequisiteIDError = "no prerequisite ID provided"
+var PrerequisiteNotFoundError = "prerequisite not found"
+var DuplicatePrerequisiteError = "skill already requires that skill"
+var PrerequisiteCycleError = "a skill can't require itself or a skill that already requires it"
+var ProblemGettingPrerequisitesError = "problem getting prerequisites"
+var UnmetPrerequisiteError = "%s can't be raised until %s is at least %d (currently %d)"
+var ProblemGettingRollsError = "problem getting rolls"
+
+var CharacterNotFoundError = "character not found"
+
+var NoEncounterIDError = "no encounter ID provided"
+var NoParticipantIDError = "no participant ID provided"
+var EncounterNotFoundError = "encounter not found"
+var ParticipantNotFoundError = "participant not found"
+var ProblemGettingEncountersError = "problem getting encounter(s)"
+var ProblemGettingParticipantsError = "problem getting participants"
+var NotEncounterHostError = "only the host can manage this encounter"
+var AlreadyParticipatingError = "character is already taking part in this encounter"
+var EncounterNotOpenError = "encounter has already started"
+var EncounterNotInProgressError = "encounter is not in progress"
+var EncounterFinishedError = "encounter is already finished"
+var EncounterHasNoParticipantsError = "encounter has no participants"
+var IncompleteTurnOrderError = "turn order must list every participant exactly once"
+var CannotDelayAfterSelfError = "a participant cannot delay until after themselves"
+var EncounterHasNoActiveParticipantsError = "everyone in this encounter is down or has fled"
+var NegativeAmountError = "amount cannot be negative"
+var UnknownStatusError = "unknown participant status"
+var ProblemGettingHealthChangesError = "problem getting health changes"
+
+var CannotAttackSelfError = "a participant cannot attack themselves"
+var AttackerCannotActError = "attacker is down or has fled"
+var DefenderFledError = "defender has fled the fight"
+var NotAttackerError = "only the host or the attacker's player can attack"
+var AttackNotFoundError = "attack not found"
+var ProblemGettingAttacksError = "problem getting attacks"
+
+var UnknownRulesetError = "unknown ruleset"
+
+var NoConditionIDError = "no condition ID provided"
+var ConditionNotFoundError = "condition not found"
+var ConditionNotActiveError = "condition has already ended"
+var ProblemGettingConditionsError = "problem getting conditions"
+
+var ImmutableEventError = "encounter events cannot be changed"
+var ProblemGettingEventsError = "problem getting encounter events"
+var BadLastEventIDError = "last event ID must be a sequence number"
+var StreamingUnsupportedError = "streaming is not supported"
+
 var NoTokenError = "no token set in headers"
 var InvalidTokenError = "invalid token pair"
 var InvalidUserTokenError = "invalid user/token pair"
 var InvalidTokenOrUnauthorizedError = "invalid token or unauthorized action"
+var OriginNotAllowedError = "live subscriptions aren't allowed from this origin"
+var MissingRoleError = "you don't have the role needed to do this"
+var UnknownRoleError = "unknown role %q"
+var ProblemCheckingTokenError = "problem checking whether the token was revoked"
+var InvalidRefreshTokenError = "invalid or expired refresh token"
+var RefreshTokenReusedError = "refresh token has already been used, please log in again"
+var InvalidResetTokenError = "invalid, expired or already used password reset"
+var TokenUnavailableError = "Token generation is unavailable. Please contact the administrator."
 
-var AuthenticationFeedback = "Unable to authenticate."
\ No newline at end of file
+var AuthenticationFeedback = "Unable to authenticate."
+var PasswordResetFeedback = "If that email belongs to a player, instructions to reset the password are on their way."
\ No newline at end of file
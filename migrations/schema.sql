@@ -15,6 +15,41 @@
 /*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
 /*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
 
+--
+-- Table structure for table `attacks`
+--
+
+DROP TABLE IF EXISTS `attacks`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `attacks` (
+  `id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `encounter_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `round` int(11) NOT NULL,
+  `attacker_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `defender_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `attack_skill_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `defense_skill_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `attack_roll_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `defense_roll_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `margin` int(11) NOT NULL,
+  `hit` tinyint(1) NOT NULL,
+  `damage` int(11) NOT NULL,
+  `health_change_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  `ruleset` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'd20',
+  `attack_score` int(11) NOT NULL DEFAULT '0',
+  `attack_success` tinyint(1) NOT NULL DEFAULT '0',
+  `defense_score` int(11) NOT NULL DEFAULT '0',
+  `defense_success` tinyint(1) NOT NULL DEFAULT '0',
+  `attack_skill_value` int(11) NOT NULL DEFAULT '0',
+  `defense_skill_value` int(11) NOT NULL DEFAULT '0',
+  PRIMARY KEY (`id`),
+  KEY `attacks_encounter_id_idx` (`encounter_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `character_sheet_entries`
 --
@@ -51,10 +86,238 @@ CREATE TABLE `characters` (
   `race` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
   `ingame_name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
   `server` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `point_budget` int(11) DEFAULT NULL,
+  `skill_set_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '3c5f7a2e-9d41-4b8e-a6f0-1e2d4c6b8a90',
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `condition_modifiers`
+--
+
+DROP TABLE IF EXISTS `condition_modifiers`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `condition_modifiers` (
+  `id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `condition_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `skill_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `modifier` int(11) NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `condition_modifiers_condition_id_idx` (`condition_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `conditions`
+--
+
+DROP TABLE IF EXISTS `conditions`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `conditions` (
+  `id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `encounter_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `participant_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `description` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `duration` int(11) NOT NULL DEFAULT '0',
+  `remaining_rounds` int(11) NOT NULL DEFAULT '0',
+  `active` tinyint(1) NOT NULL DEFAULT '1',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `conditions_participant_id_idx` (`participant_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `encounter_events`
+--
+
+DROP TABLE IF EXISTS `encounter_events`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `encounter_events` (
+  `id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `encounter_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `sequence` int(11) NOT NULL,
+  `round` int(11) NOT NULL,
+  `kind` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `participant_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `message` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `payload` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `encounter_events_encounter_id_sequence_idx` (`encounter_id`,`sequence`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `encounter_participants`
+--
+
+DROP TABLE IF EXISTS `encounter_participants`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `encounter_participants` (
+  `id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `encounter_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `character_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `turn_order` int(11) NOT NULL DEFAULT '0',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  `initiative` int(11) NOT NULL DEFAULT '0',
+  `initiative_modifier` int(11) NOT NULL DEFAULT '0',
+  `initiative_roll_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+  `delayed` tinyint(1) NOT NULL DEFAULT '0',
+  `health` int(11) NOT NULL DEFAULT '0',
+  `max_health` int(11) NOT NULL DEFAULT '0',
+  `status` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'active',
+  PRIMARY KEY (`id`),
+  KEY `encounter_participants_encounter_id_idx` (`encounter_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `encounters`
+--
+
+DROP TABLE IF EXISTS `encounters`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `encounters` (
+  `id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `host_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `status` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'open',
+  `round` int(11) NOT NULL DEFAULT '0',
+  `turn` int(11) NOT NULL DEFAULT '0',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  `initiative_skill_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+  `health_skill_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+  `base_health` int(11) NOT NULL DEFAULT '10',
+  `ruleset` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'd20',
   PRIMARY KEY (`id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `health_changes`
+--
+
+DROP TABLE IF EXISTS `health_changes`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `health_changes` (
+  `id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `encounter_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `participant_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `kind` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `amount` int(11) NOT NULL,
+  `reason` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `health` int(11) NOT NULL,
+  `status` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `health_changes_encounter_id_idx` (`encounter_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `password_resets`
+--
+
+DROP TABLE IF EXISTS `password_resets`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `password_resets` (
+  `id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `user_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `token_hash` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `expires_at` datetime NOT NULL,
+  `used_at` datetime DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `password_resets_token_hash_idx` (`token_hash`),
+  KEY `password_resets_user_id_idx` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `refresh_tokens`
+--
+
+DROP TABLE IF EXISTS `refresh_tokens`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `refresh_tokens` (
+  `id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `user_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `family_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `token_hash` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `expires_at` datetime NOT NULL,
+  `used_at` datetime DEFAULT NULL,
+  `revoked_at` datetime DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `refresh_tokens_token_hash_idx` (`token_hash`),
+  KEY `refresh_tokens_family_id_idx` (`family_id`),
+  KEY `refresh_tokens_user_id_idx` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `revoked_tokens`
+--
+
+DROP TABLE IF EXISTS `revoked_tokens`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `revoked_tokens` (
+  `id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `token_hash` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `expires_at` datetime NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `revoked_tokens_token_hash_idx` (`token_hash`),
+  KEY `revoked_tokens_expires_at_idx` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `rolls`
+--
+
+DROP TABLE IF EXISTS `rolls`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `rolls` (
+  `id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `character_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `skill_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `expression` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `modifier` int(11) NOT NULL,
+  `total` int(11) NOT NULL,
+  `detail` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `breakdown` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  `skill_value` int(11) NOT NULL DEFAULT '0',
+  PRIMARY KEY (`id`),
+  KEY `rolls_character_id_idx` (`character_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `schema_migration`
 --
@@ -68,6 +331,105 @@ CREATE TABLE `schema_migration` (
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `sheet_entry_changes`
+--
+
+DROP TABLE IF EXISTS `sheet_entry_changes`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `sheet_entry_changes` (
+  `id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `character_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `sequence` int(11) NOT NULL,
+  `sheet_entry_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `skill_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `old_value` int(11) DEFAULT NULL,
+  `new_value` int(11) DEFAULT NULL,
+  `changed_by` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `sheet_entry_changes_character_id_sequence_idx` (`character_id`,`sequence`),
+  KEY `sheet_entry_changes_character_id_created_at_idx` (`character_id`,`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `sheet_snapshot_entries`
+--
+
+DROP TABLE IF EXISTS `sheet_snapshot_entries`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `sheet_snapshot_entries` (
+  `id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `snapshot_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `skill_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `value` int(11) NOT NULL,
+  `note` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `sheet_snapshot_entries_snapshot_id_idx` (`snapshot_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `sheet_snapshots`
+--
+
+DROP TABLE IF EXISTS `sheet_snapshots`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `sheet_snapshots` (
+  `id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `character_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created_by` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `sheet_snapshots_character_id_name_idx` (`character_id`,`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `skill_prerequisites`
+--
+
+DROP TABLE IF EXISTS `skill_prerequisites`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `skill_prerequisites` (
+  `id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `skill_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `required_skill_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `minimum_value` int(11) NOT NULL DEFAULT '0',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `skill_prerequisites_skill_id_required_skill_id_idx` (`skill_id`,`required_skill_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `skill_sets`
+--
+
+DROP TABLE IF EXISTS `skill_sets`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `skill_sets` (
+  `id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `description` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `skills`
 --
@@ -84,7 +446,12 @@ CREATE TABLE `skills` (
   `updated_at` datetime NOT NULL,
   `cost` int(11) NOT NULL DEFAULT '3',
   `starting_value` int(11) NOT NULL DEFAULT '0',
-  PRIMARY KEY (`id`)
+  `min_value` int(11) DEFAULT NULL,
+  `max_value` int(11) DEFAULT NULL,
+  `child_rule` varchar(16) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `skill_set_id` char(36) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '3c5f7a2e-9d41-4b8e-a6f0-1e2d4c6b8a90',
+  PRIMARY KEY (`id`),
+  KEY `skills_skill_set_id_idx` (`skill_set_id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
@@ -102,6 +469,7 @@ CREATE TABLE `users` (
   `password_hash` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
   `created_at` datetime NOT NULL,
   `updated_at` datetime NOT NULL,
+  `role` varchar(16) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'player',
   PRIMARY KEY (`id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
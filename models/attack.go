@@ -0,0 +1,80 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+)
+
+// Attack - one opposed exchange between two participants of an encounter
+type Attack struct {
+	ID                uuid.UUID `json:"id" db:"id"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+	EncounterID       uuid.UUID `json:"encounter_id" db:"encounter_id"`
+	Round             int       `json:"round" db:"round"`
+	AttackerID        uuid.UUID `json:"attacker_id" db:"attacker_id"`
+	DefenderID        uuid.UUID `json:"defender_id" db:"defender_id"`
+	AttackSkillID     uuid.UUID `json:"attack_skill_id" db:"attack_skill_id"`
+	DefenseSkillID    uuid.UUID `json:"defense_skill_id" db:"defense_skill_id"`
+	AttackRollID      uuid.UUID `json:"attack_roll_id" db:"attack_roll_id"`
+	DefenseRollID     uuid.UUID `json:"defense_roll_id" db:"defense_roll_id"`
+	Ruleset           string    `json:"ruleset" db:"ruleset"`
+	AttackSkillValue  int       `json:"attack_skill_value" db:"attack_skill_value"`
+	AttackScore       int       `json:"attack_score" db:"attack_score"`
+	AttackSuccess     bool      `json:"attack_success" db:"attack_success"`
+	DefenseSkillValue int       `json:"defense_skill_value" db:"defense_skill_value"`
+	DefenseScore      int       `json:"defense_score" db:"defense_score"`
+	DefenseSuccess    bool      `json:"defense_success" db:"defense_success"`
+	Margin            int       `json:"margin" db:"margin"`
+	Hit               bool      `json:"hit" db:"hit"`
+	Damage            int       `json:"damage" db:"damage"`
+	HealthChangeID    uuid.UUID `json:"health_change_id" db:"health_change_id"`
+}
+
+// AttackJSON - used to marshal the incoming JSON when one participant attacks another.
+// Skills can be given by ID or by name.
+type AttackJSON struct {
+	AttackerID     uuid.UUID `json:"attacker_id"`
+	DefenderID     uuid.UUID `json:"defender_id"`
+	AttackSkillID  uuid.UUID `json:"attack_skill_id"`
+	AttackSkill    string    `json:"attack_skill"`
+	DefenseSkillID uuid.UUID `json:"defense_skill_id"`
+	DefenseSkill   string    `json:"defense_skill"`
+}
+
+// String is not required by pop and may be deleted
+func (a Attack) String() string {
+	ja, _ := json.Marshal(a)
+	return string(ja)
+}
+
+// Attacks is not required by pop and may be deleted
+type Attacks []Attack
+
+// String is not required by pop and may be deleted
+func (a Attacks) String() string {
+	ja, _ := json.Marshal(a)
+	return string(ja)
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (a *Attack) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (a *Attack) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (a *Attack) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/gobuffalo/nulls"
 	"github.com/gobuffalo/pop"
 	"github.com/gobuffalo/uuid"
 	"github.com/gobuffalo/validate"
@@ -12,15 +13,17 @@ import (
 
 // Character - a character
 type Character struct {
-	ID         uuid.UUID `json:"id" db:"id"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
-	Name       string    `json:"name" db:"name"`
-	PlayerID   uuid.UUID `json:"player_id" db:"player_id"`
-	Race       string    `json:"race" db:"race"`
-	Gender     string    `json:"gender" db:"gender"`
-	IngameName string    `json:"ingame_name" db:"ingame_name"`
-	Server     string    `json:"server" db:"server"`
+	ID          uuid.UUID `json:"id" db:"id"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	Name        string    `json:"name" db:"name"`
+	PlayerID    uuid.UUID `json:"player_id" db:"player_id"`
+	Race        string    `json:"race" db:"race"`
+	Gender      string    `json:"gender" db:"gender"`
+	IngameName  string    `json:"ingame_name" db:"ingame_name"`
+	Server      string    `json:"server" db:"server"`
+	PointBudget nulls.Int `json:"point_budget" db:"point_budget"`
+	SkillSetID  uuid.UUID `json:"skill_set_id" db:"skill_set_id"`
 }
 
 // String is not required by pop and may be deleted
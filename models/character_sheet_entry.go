@@ -2,12 +2,13 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
+	"github.com/dosaki/emote_combat_server/messages"
 	"github.com/gobuffalo/pop"
 	"github.com/gobuffalo/uuid"
 	"github.com/gobuffalo/validate"
-	"github.com/gobuffalo/validate/validators"
 )
 
 // CharacterSheetEntry - An entry of a character sheet: basically a skill and it's value
@@ -38,11 +39,19 @@ func (c CharacterSheetEntries) String() string {
 
 // Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
 // This method is not required and may be deleted.
+// The value has to be within the skill's bounds, unless the skill is worked out from its subskills
 func (c *CharacterSheetEntry) Validate(tx *pop.Connection) (*validate.Errors, error) {
-	return validate.Validate(
-		&validators.IntIsPresent{Field: c.Value, Name: "Value"},
-		&validators.StringIsPresent{Field: c.Note, Name: "Note"},
-	), nil
+	verrs := validate.NewErrors()
+	var skills []Skill
+	if err := tx.Where("id = ?", c.SkillID).All(&skills); err != nil {
+		return verrs, err
+	}
+	if len(skills) == 0 {
+		verrs.Add("skill_id", fmt.Sprintf(messages.SheetEntrySkillNotFoundError, c.SkillID))
+	} else if !skills[0].IsDerived() && !skills[0].InBounds(c.Value) {
+		verrs.Add("value", fmt.Sprintf(messages.OutOfBoundsError, skills[0].Name, c.Value, skills[0].Bounds()))
+	}
+	return verrs, nil
 }
 
 // ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+)
+
+// Condition - a named effect on an encounter participant such as a stun, a bleed or a buff.
+// A duration of 0 means it lasts until it is removed.
+type Condition struct {
+	ID              uuid.UUID           `json:"id" db:"id"`
+	CreatedAt       time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at" db:"updated_at"`
+	EncounterID     uuid.UUID           `json:"encounter_id" db:"encounter_id"`
+	ParticipantID   uuid.UUID           `json:"participant_id" db:"participant_id"`
+	Name            string              `json:"name" db:"name"`
+	Description     string              `json:"description" db:"description"`
+	Duration        int                 `json:"duration" db:"duration"`
+	RemainingRounds int                 `json:"remaining_rounds" db:"remaining_rounds"`
+	Active          bool                `json:"active" db:"active"`
+	Modifiers       []ConditionModifier `json:"modifiers" db:"-"`
+}
+
+// ConditionModifier - how much a condition changes one skill while it is active
+type ConditionModifier struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ConditionID uuid.UUID `json:"condition_id" db:"condition_id"`
+	SkillID     uuid.UUID `json:"skill_id" db:"skill_id"`
+	Modifier    int       `json:"modifier" db:"modifier"`
+}
+
+// Tick - a round of the condition passes; returns whether it just wore off
+func (c *Condition) Tick() bool {
+	if !c.Active || c.Duration == 0 {
+		return false
+	}
+	c.RemainingRounds--
+	if c.RemainingRounds <= 0 {
+		c.RemainingRounds = 0
+		c.Active = false
+		return true
+	}
+	return false
+}
+
+// ModifierFor - the total this condition adds to a skill while active
+func (c Condition) ModifierFor(skillID uuid.UUID) int {
+	total := 0
+	if !c.Active {
+		return total
+	}
+	for _, m := range c.Modifiers {
+		if m.SkillID == skillID {
+			total += m.Modifier
+		}
+	}
+	return total
+}
+
+// String is not required by pop and may be deleted
+func (c Condition) String() string {
+	jc, _ := json.Marshal(c)
+	return string(jc)
+}
+
+// Conditions is not required by pop and may be deleted
+type Conditions []Condition
+
+// String is not required by pop and may be deleted
+func (c Conditions) String() string {
+	jc, _ := json.Marshal(c)
+	return string(jc)
+}
+
+// ModifierFor - what all the active conditions add to a skill between them
+func (c Conditions) ModifierFor(skillID uuid.UUID) int {
+	total := 0
+	for _, condition := range c {
+		total += condition.ModifierFor(skillID)
+	}
+	return total
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (c *Condition) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: c.Name, Name: "Name"},
+		&validators.IntIsGreaterThan{Field: c.Duration, Name: "Duration", Compared: -1},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (c *Condition) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (c *Condition) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
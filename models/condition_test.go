@@ -0,0 +1,56 @@
+package models_test
+
+import (
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/uuid"
+)
+
+func (ms *ModelSuite) Test_Condition_Tick() {
+	tests := []struct {
+		name          string
+		duration      int
+		remaining     int
+		active        bool
+		wantEnded     bool
+		wantRemaining int
+		wantActive    bool
+	}{
+		{"counts down", 3, 3, true, false, 2, true},
+		{"wears off on the last round", 3, 1, true, true, 0, false},
+		{"never goes below 0", 3, 0, true, true, 0, false},
+		{"lasts until removed", 0, 0, true, false, 0, true},
+		{"already worn off", 3, 0, false, false, 0, false},
+	}
+	for _, tt := range tests {
+		c := &models.Condition{Duration: tt.duration, RemainingRounds: tt.remaining, Active: tt.active}
+		ms.Equal(tt.wantEnded, c.Tick(), tt.name)
+		ms.Equal(tt.wantRemaining, c.RemainingRounds, tt.name)
+		ms.Equal(tt.wantActive, c.Active, tt.name)
+	}
+}
+
+func (ms *ModelSuite) Test_Conditions_ModifierFor() {
+	swords := uuid.Must(uuid.NewV4())
+	archery := uuid.Must(uuid.NewV4())
+	blessed := models.Condition{Active: true, Modifiers: []models.ConditionModifier{{SkillID: swords, Modifier: 2}, {SkillID: archery, Modifier: 1}}}
+	stunned := models.Condition{Active: true, Modifiers: []models.ConditionModifier{{SkillID: swords, Modifier: -3}}}
+	enraged := models.Condition{Active: true, Modifiers: []models.ConditionModifier{{SkillID: swords, Modifier: 1}, {SkillID: swords, Modifier: 1}}}
+	wornOff := models.Condition{Active: false, Modifiers: []models.ConditionModifier{{SkillID: swords, Modifier: 5}}}
+
+	tests := []struct {
+		name       string
+		conditions models.Conditions
+		skillID    uuid.UUID
+		want       int
+	}{
+		{"none", models.Conditions{}, swords, 0},
+		{"one", models.Conditions{blessed}, swords, 2},
+		{"stacks across conditions", models.Conditions{blessed, stunned}, swords, -1},
+		{"stacks within a condition", models.Conditions{enraged, blessed}, swords, 4},
+		{"only the skill asked for", models.Conditions{blessed, stunned}, archery, 1},
+		{"worn off conditions add nothing", models.Conditions{blessed, wornOff}, swords, 2},
+	}
+	for _, tt := range tests {
+		ms.Equal(tt.want, tt.conditions.ModifierFor(tt.skillID), tt.name)
+	}
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/services/ruleset"
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+)
+
+// EncounterOpen - the encounter is accepting participants but hasn't started
+const EncounterOpen = "open"
+
+// EncounterInProgress - the fight is on
+const EncounterInProgress = "in_progress"
+
+// EncounterFinished - the fight is over and can no longer change
+const EncounterFinished = "finished"
+
+// Encounter - a fight between characters
+type Encounter struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	Name      string    `json:"name" db:"name"`
+	HostID    uuid.UUID `json:"host_id" db:"host_id"`
+	Status    string    `json:"status" db:"status"`
+	Round     int       `json:"round" db:"round"`
+	Turn      int       `json:"turn" db:"turn"`
+
+	InitiativeSkillID uuid.UUID `json:"initiative_skill_id" db:"initiative_skill_id"`
+	HealthSkillID     uuid.UUID `json:"health_skill_id" db:"health_skill_id"`
+	BaseHealth        int       `json:"base_health" db:"base_health"`
+	Ruleset           string    `json:"ruleset" db:"ruleset"`
+}
+
+// EncounterUpdateJSON - used to marshal the incoming JSON when the host changes an encounter; fields left out keep their value
+type EncounterUpdateJSON struct {
+	Name              *string    `json:"name"`
+	InitiativeSkillID *uuid.UUID `json:"initiative_skill_id"`
+	HealthSkillID     *uuid.UUID `json:"health_skill_id"`
+	BaseHealth        *int       `json:"base_health"`
+	Ruleset           *string    `json:"ruleset"`
+}
+
+// DefaultBaseHealth - the health everyone gets when the host doesn't pick a value
+const DefaultBaseHealth = 10
+
+// Start - moves an open encounter to its first round
+func (e *Encounter) Start(participants int) error {
+	if e.Status != EncounterOpen {
+		return errors.New(messages.EncounterNotOpenError)
+	}
+	if participants == 0 {
+		return errors.New(messages.EncounterHasNoParticipantsError)
+	}
+	e.Status = EncounterInProgress
+	e.Round = 1
+	e.Turn = 0
+	return nil
+}
+
+// Advance - moves to the next participant still in the fight, starting a new round once everyone has acted
+func (e *Encounter) Advance(participants []EncounterParticipant) error {
+	if e.Status != EncounterInProgress {
+		return errors.New(messages.EncounterNotInProgressError)
+	}
+	if len(participants) == 0 {
+		return errors.New(messages.EncounterHasNoParticipantsError)
+	}
+	for range participants {
+		e.Turn++
+		if e.Turn >= len(participants) {
+			e.Turn = 0
+			e.Round++
+		}
+		if participants[e.Turn].CanAct() {
+			return nil
+		}
+	}
+	return errors.New(messages.EncounterHasNoActiveParticipantsError)
+}
+
+// Leave - closes the gap a participant leaves in the turn order. If they held the last turn of the round,
+// the turn wraps to the top and a new round begins.
+func (e *Encounter) Leave(turnOrder int, remaining int) {
+	if turnOrder < e.Turn {
+		e.Turn--
+	}
+	if e.Turn >= remaining {
+		e.Turn = 0
+		if e.Status == EncounterInProgress && remaining > 0 {
+			e.Round++
+		}
+	}
+}
+
+// Close - finishes the encounter
+func (e *Encounter) Close() error {
+	if e.Status == EncounterFinished {
+		return errors.New(messages.EncounterFinishedError)
+	}
+	e.Status = EncounterFinished
+	return nil
+}
+
+// String is not required by pop and may be deleted
+func (e Encounter) String() string {
+	je, _ := json.Marshal(e)
+	return string(je)
+}
+
+// Encounters is not required by pop and may be deleted
+type Encounters []Encounter
+
+// String is not required by pop and may be deleted
+func (e Encounters) String() string {
+	je, _ := json.Marshal(e)
+	return string(je)
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (e *Encounter) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: e.Name, Name: "Name"},
+		&validators.StringInclusion{Field: e.Status, Name: "Status", List: []string{EncounterOpen, EncounterInProgress, EncounterFinished}},
+		&validators.IntIsGreaterThan{Field: e.BaseHealth, Name: "BaseHealth", Compared: -1},
+		&validators.FuncValidator{
+			Field:   e.Ruleset,
+			Name:    "Ruleset",
+			Message: "%s is not a known ruleset",
+			Fn: func() bool {
+				_, err := ruleset.Get(e.Ruleset)
+				return err == nil
+			},
+		},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (e *Encounter) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (e *Encounter) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
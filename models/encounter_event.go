@@ -0,0 +1,103 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+)
+
+// Kinds of encounter event
+const (
+	EventCreated          = "created"
+	EventJoined           = "joined"
+	EventLeft             = "left"
+	EventStarted          = "started"
+	EventTurn             = "turn"
+	EventFinished         = "finished"
+	EventInitiative       = "initiative"
+	EventTurnOrder        = "turn_order"
+	EventAttack           = "attack"
+	EventDamage           = "damage"
+	EventHeal             = "heal"
+	EventStatus           = "status"
+	EventConditionApplied = "condition_applied"
+	EventConditionEnded   = "condition_ended"
+)
+
+// EncounterEvent - one entry of an encounter's log. Events are numbered per encounter and never change once written.
+type EncounterEvent struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	EncounterID   uuid.UUID `json:"encounter_id" db:"encounter_id"`
+	Sequence      int       `json:"sequence" db:"sequence"`
+	Round         int       `json:"round" db:"round"`
+	Kind          string    `json:"kind" db:"kind"`
+	ParticipantID uuid.UUID `json:"participant_id" db:"participant_id"`
+	Message       string    `json:"message" db:"message"`
+	Payload       string    `json:"payload" db:"payload"`
+}
+
+// BeforeUpdate - the log is append only
+func (e *EncounterEvent) BeforeUpdate(tx *pop.Connection) error {
+	return errors.New(messages.ImmutableEventError)
+}
+
+// BeforeDestroy - the log is append only
+func (e *EncounterEvent) BeforeDestroy(tx *pop.Connection) error {
+	return errors.New(messages.ImmutableEventError)
+}
+
+// MarshalJSON - sends the payload as JSON rather than as a string holding JSON
+func (e EncounterEvent) MarshalJSON() ([]byte, error) {
+	type event EncounterEvent
+	payload := json.RawMessage(e.Payload)
+	if len(e.Payload) == 0 {
+		payload = json.RawMessage("null")
+	}
+	return json.Marshal(struct {
+		event
+		Payload json.RawMessage `json:"payload"`
+	}{event(e), payload})
+}
+
+// String is not required by pop and may be deleted
+func (e EncounterEvent) String() string {
+	je, _ := json.Marshal(e)
+	return string(je)
+}
+
+// EncounterEvents is not required by pop and may be deleted
+type EncounterEvents []EncounterEvent
+
+// String is not required by pop and may be deleted
+func (e EncounterEvents) String() string {
+	je, _ := json.Marshal(e)
+	return string(je)
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (e *EncounterEvent) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: e.Kind, Name: "Kind"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (e *EncounterEvent) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (e *EncounterEvent) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
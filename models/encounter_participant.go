@@ -0,0 +1,111 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+)
+
+// ParticipantActive - still fighting
+const ParticipantActive = "active"
+
+// ParticipantDowned - out of health
+const ParticipantDowned = "downed"
+
+// ParticipantFled - left the fight
+const ParticipantFled = "fled"
+
+// ParticipantStatuses - every status a participant can have
+var ParticipantStatuses = []string{ParticipantActive, ParticipantDowned, ParticipantFled}
+
+// EncounterParticipant - a character taking part in an encounter
+type EncounterParticipant struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	EncounterID uuid.UUID `json:"encounter_id" db:"encounter_id"`
+	CharacterID uuid.UUID `json:"character_id" db:"character_id"`
+	TurnOrder   int       `json:"turn_order" db:"turn_order"`
+
+	Initiative         int       `json:"initiative" db:"initiative"`
+	InitiativeModifier int       `json:"initiative_modifier" db:"initiative_modifier"`
+	InitiativeRollID   uuid.UUID `json:"initiative_roll_id" db:"initiative_roll_id"`
+	Delayed            bool      `json:"delayed" db:"delayed"`
+
+	Health    int    `json:"health" db:"health"`
+	MaxHealth int    `json:"max_health" db:"max_health"`
+	Status    string `json:"status" db:"status"`
+}
+
+// TurnOrderJSON - used to marshal the incoming JSON when the host sets the turn order by hand
+type TurnOrderJSON struct {
+	Order []uuid.UUID `json:"order"`
+}
+
+// DelayJSON - used to marshal the incoming JSON when a participant delays their turn
+type DelayJSON struct {
+	After uuid.UUID `json:"after"`
+}
+
+// CanAct - whether the participant still takes turns
+func (e EncounterParticipant) CanAct() bool {
+	return e.Status == ParticipantActive
+}
+
+// HasRolledInitiative - whether the participant has a place in the initiative order yet
+func (e EncounterParticipant) HasRolledInitiative() bool {
+	return e.InitiativeRollID != uuid.Nil
+}
+
+// GoesBefore - initiative order: highest total first, then highest modifier, then character ID so ties always resolve the same way
+func (e EncounterParticipant) GoesBefore(other EncounterParticipant) bool {
+	if e.HasRolledInitiative() != other.HasRolledInitiative() {
+		return e.HasRolledInitiative()
+	}
+	if e.Initiative != other.Initiative {
+		return e.Initiative > other.Initiative
+	}
+	if e.InitiativeModifier != other.InitiativeModifier {
+		return e.InitiativeModifier > other.InitiativeModifier
+	}
+	return e.CharacterID.String() < other.CharacterID.String()
+}
+
+// String is not required by pop and may be deleted
+func (e EncounterParticipant) String() string {
+	je, _ := json.Marshal(e)
+	return string(je)
+}
+
+// EncounterParticipants is not required by pop and may be deleted
+type EncounterParticipants []EncounterParticipant
+
+// String is not required by pop and may be deleted
+func (e EncounterParticipants) String() string {
+	je, _ := json.Marshal(e)
+	return string(je)
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (e *EncounterParticipant) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringInclusion{Field: e.Status, Name: "Status", List: ParticipantStatuses},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (e *EncounterParticipant) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (e *EncounterParticipant) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
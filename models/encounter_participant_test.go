@@ -0,0 +1,27 @@
+package models_test
+
+import (
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/uuid"
+)
+
+func (ms *ModelSuite) Test_EncounterParticipant_GoesBefore() {
+	rolled := uuid.Must(uuid.NewV4())
+	low := uuid.FromStringOrNil("00000000-0000-4000-8000-000000000001")
+	high := uuid.FromStringOrNil("00000000-0000-4000-8000-000000000002")
+
+	tests := []struct {
+		name  string
+		first models.EncounterParticipant
+		then  models.EncounterParticipant
+	}{
+		{"higher total", models.EncounterParticipant{InitiativeRollID: rolled, Initiative: 12}, models.EncounterParticipant{InitiativeRollID: rolled, Initiative: 8, InitiativeModifier: 5}},
+		{"higher modifier on a tie", models.EncounterParticipant{InitiativeRollID: rolled, Initiative: 10, InitiativeModifier: 3}, models.EncounterParticipant{InitiativeRollID: rolled, Initiative: 10, InitiativeModifier: 1}},
+		{"character ID on a full tie", models.EncounterParticipant{InitiativeRollID: rolled, Initiative: 10, CharacterID: low}, models.EncounterParticipant{InitiativeRollID: rolled, Initiative: 10, CharacterID: high}},
+		{"rolled before not rolled", models.EncounterParticipant{InitiativeRollID: rolled, Initiative: -2}, models.EncounterParticipant{Initiative: 20}},
+	}
+	for _, tt := range tests {
+		ms.True(tt.first.GoesBefore(tt.then), tt.name)
+		ms.False(tt.then.GoesBefore(tt.first), tt.name)
+	}
+}
@@ -0,0 +1,66 @@
+package models_test
+
+import (
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+)
+
+func (ms *ModelSuite) Test_Encounter_Leave() {
+	tests := []struct {
+		name      string
+		status    string
+		turn      int
+		turnOrder int
+		remaining int
+		wantTurn  int
+		wantRound int
+	}{
+		{"someone who already acted", models.EncounterInProgress, 2, 0, 3, 1, 1},
+		{"someone still to act", models.EncounterInProgress, 1, 2, 2, 1, 1},
+		{"whoever holds the turn", models.EncounterInProgress, 1, 1, 2, 1, 1},
+		{"the last one on their turn", models.EncounterInProgress, 2, 2, 2, 0, 2},
+		{"the last one before the fight", models.EncounterOpen, 0, 0, 0, 0, 1},
+	}
+	for _, tt := range tests {
+		e := &models.Encounter{Status: tt.status, Round: 1, Turn: tt.turn}
+		e.Leave(tt.turnOrder, tt.remaining)
+		ms.Equal(tt.wantTurn, e.Turn, tt.name)
+		ms.Equal(tt.wantRound, e.Round, tt.name)
+	}
+}
+
+func (ms *ModelSuite) Test_Encounter_Advance() {
+	active := models.EncounterParticipant{Status: models.ParticipantActive}
+	downed := models.EncounterParticipant{Status: models.ParticipantDowned}
+	fled := models.EncounterParticipant{Status: models.ParticipantFled}
+
+	tests := []struct {
+		name         string
+		status       string
+		turn         int
+		participants []models.EncounterParticipant
+		wantTurn     int
+		wantRound    int
+		wantErr      string
+	}{
+		{"to the next", models.EncounterInProgress, 0, []models.EncounterParticipant{active, active, active}, 1, 1, ""},
+		{"wraps into a new round", models.EncounterInProgress, 2, []models.EncounterParticipant{active, active, active}, 0, 2, ""},
+		{"skips those out of the fight", models.EncounterInProgress, 0, []models.EncounterParticipant{active, downed, fled, active}, 3, 1, ""},
+		{"skips across the round", models.EncounterInProgress, 1, []models.EncounterParticipant{downed, active, downed}, 1, 2, ""},
+		{"no one can act", models.EncounterInProgress, 0, []models.EncounterParticipant{downed, fled}, 0, 0, messages.EncounterHasNoActiveParticipantsError},
+		{"no one there", models.EncounterInProgress, 0, []models.EncounterParticipant{}, 0, 0, messages.EncounterHasNoParticipantsError},
+		{"not started", models.EncounterOpen, 0, []models.EncounterParticipant{active}, 0, 0, messages.EncounterNotInProgressError},
+		{"finished", models.EncounterFinished, 0, []models.EncounterParticipant{active}, 0, 0, messages.EncounterNotInProgressError},
+	}
+	for _, tt := range tests {
+		e := &models.Encounter{Status: tt.status, Round: 1, Turn: tt.turn}
+		err := e.Advance(tt.participants)
+		if tt.wantErr != "" {
+			ms.EqualError(err, tt.wantErr, tt.name)
+			continue
+		}
+		ms.NoError(err, tt.name)
+		ms.Equal(tt.wantTurn, e.Turn, tt.name)
+		ms.Equal(tt.wantRound, e.Round, tt.name)
+	}
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+)
+
+// HealthDamage - a participant lost health
+const HealthDamage = "damage"
+
+// HealthHeal - a participant regained health
+const HealthHeal = "heal"
+
+// HealthStatus - a participant's status was changed by hand
+const HealthStatus = "status"
+
+// HealthChange - a record of a participant being hurt, healed or having their status changed
+type HealthChange struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	EncounterID   uuid.UUID `json:"encounter_id" db:"encounter_id"`
+	ParticipantID uuid.UUID `json:"participant_id" db:"participant_id"`
+	Kind          string    `json:"kind" db:"kind"`
+	Amount        int       `json:"amount" db:"amount"`
+	Reason        string    `json:"reason" db:"reason"`
+	Health        int       `json:"health" db:"health"`
+	Status        string    `json:"status" db:"status"`
+}
+
+// HealthChangeJSON - used to marshal the incoming JSON when damaging or healing a participant
+type HealthChangeJSON struct {
+	Amount int    `json:"amount"`
+	Reason string `json:"reason"`
+}
+
+// StatusChangeJSON - used to marshal the incoming JSON when changing a participant's status
+type StatusChangeJSON struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// String is not required by pop and may be deleted
+func (h HealthChange) String() string {
+	jh, _ := json.Marshal(h)
+	return string(jh)
+}
+
+// HealthChanges is not required by pop and may be deleted
+type HealthChanges []HealthChange
+
+// String is not required by pop and may be deleted
+func (h HealthChanges) String() string {
+	jh, _ := json.Marshal(h)
+	return string(jh)
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (h *HealthChange) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringInclusion{Field: h.Kind, Name: "Kind", List: []string{HealthDamage, HealthHeal, HealthStatus}},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (h *HealthChange) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (h *HealthChange) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
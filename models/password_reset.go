@@ -0,0 +1,76 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+)
+
+// PasswordReset - a single use token that lets a user choose a new password for a limited time.
+// Only a hash of the token is kept
+type PasswordReset struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    nulls.Time `json:"used_at" db:"used_at"`
+}
+
+// PasswordForgotJSON - used to marshal the incoming JSON when asking for a password reset
+type PasswordForgotJSON struct {
+	Email string `json:"email"`
+}
+
+// PasswordResetJSON - used to marshal the incoming JSON when resetting a password
+type PasswordResetJSON struct {
+	Token                string `json:"token"`
+	Password             string `json:"password"`
+	PasswordConfirmation string `json:"confirmPassword"`
+}
+
+// Usable - whether the reset can still be used
+func (p PasswordReset) Usable(now time.Time) bool {
+	return !p.UsedAt.Valid && now.Before(p.ExpiresAt)
+}
+
+// String is not required by pop and may be deleted
+func (p PasswordReset) String() string {
+	jp, _ := json.Marshal(p)
+	return string(jp)
+}
+
+// PasswordResets is not required by pop and may be deleted
+type PasswordResets []PasswordReset
+
+// String is not required by pop and may be deleted
+func (p PasswordResets) String() string {
+	jp, _ := json.Marshal(p)
+	return string(jp)
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (p *PasswordReset) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: p.TokenHash, Name: "TokenHash"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (p *PasswordReset) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (p *PasswordReset) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+)
+
+// RefreshToken - a single use token that can be traded for a new access token and a new refresh token.
+// Every token traded from the same login shares a family, so the whole login can be revoked at once.
+// Only a hash of the token is kept
+type RefreshToken struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	FamilyID  uuid.UUID  `json:"family_id" db:"family_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    nulls.Time `json:"used_at" db:"used_at"`
+	RevokedAt nulls.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+// RefreshTokenJSON - used to marshal the incoming JSON when refreshing a token
+type RefreshTokenJSON struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// Usable - whether the token can still be traded in
+func (r RefreshToken) Usable(now time.Time) bool {
+	return !r.UsedAt.Valid && !r.RevokedAt.Valid && now.Before(r.ExpiresAt)
+}
+
+// String is not required by pop and may be deleted
+func (r RefreshToken) String() string {
+	jr, _ := json.Marshal(r)
+	return string(jr)
+}
+
+// RefreshTokens is not required by pop and may be deleted
+type RefreshTokens []RefreshToken
+
+// String is not required by pop and may be deleted
+func (r RefreshTokens) String() string {
+	jr, _ := json.Marshal(r)
+	return string(jr)
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (r *RefreshToken) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: r.TokenHash, Name: "TokenHash"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (r *RefreshToken) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (r *RefreshToken) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
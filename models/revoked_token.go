@@ -0,0 +1,55 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+)
+
+// RevokedToken - a token that was logged out before it expired. Only a hash of the token is kept
+type RevokedToken struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	TokenHash string    `json:"token_hash" db:"token_hash"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+}
+
+// String is not required by pop and may be deleted
+func (r RevokedToken) String() string {
+	jr, _ := json.Marshal(r)
+	return string(jr)
+}
+
+// RevokedTokens is not required by pop and may be deleted
+type RevokedTokens []RevokedToken
+
+// String is not required by pop and may be deleted
+func (r RevokedTokens) String() string {
+	jr, _ := json.Marshal(r)
+	return string(jr)
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (r *RevokedToken) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: r.TokenHash, Name: "TokenHash"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (r *RevokedToken) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (r *RevokedToken) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
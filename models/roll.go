@@ -0,0 +1,88 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/dosaki/emote_combat_server/services/dice"
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+)
+
+// Roll - a dice roll made by a character against one of their skills
+type Roll struct {
+	ID            uuid.UUID   `json:"id" db:"id"`
+	CreatedAt     time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at" db:"updated_at"`
+	CharacterID   uuid.UUID   `json:"character_id" db:"character_id"`
+	SkillID       uuid.UUID   `json:"skill_id" db:"skill_id"`
+	SkillValue    int         `json:"skill_value" db:"skill_value"`
+	Expression    string      `json:"expression" db:"expression"`
+	Modifier      int         `json:"modifier" db:"modifier"`
+	Total         int         `json:"total" db:"total"`
+	Detail        string      `json:"detail" db:"detail"`
+	Breakdown     dice.Result `json:"breakdown" db:"-"`
+	BreakdownJSON string      `json:"-" db:"breakdown"`
+}
+
+// RollRequestJSON - used to marshal the incoming JSON when asking for a roll
+type RollRequestJSON struct {
+	SkillID    uuid.UUID `json:"skill_id"`
+	Skill      string    `json:"skill"`
+	Expression string    `json:"expression"`
+}
+
+// BeforeSave - stores the breakdown as JSON
+func (r *Roll) BeforeSave(tx *pop.Connection) error {
+	breakdown, err := json.Marshal(r.Breakdown)
+	if err != nil {
+		return err
+	}
+	r.BreakdownJSON = string(breakdown)
+	return nil
+}
+
+// AfterFind - restores the breakdown from its JSON
+func (r *Roll) AfterFind(tx *pop.Connection) error {
+	if len(r.BreakdownJSON) == 0 {
+		return nil
+	}
+	return json.Unmarshal([]byte(r.BreakdownJSON), &r.Breakdown)
+}
+
+// String is not required by pop and may be deleted
+func (r Roll) String() string {
+	jr, _ := json.Marshal(r)
+	return string(jr)
+}
+
+// Rolls is not required by pop and may be deleted
+type Rolls []Roll
+
+// String is not required by pop and may be deleted
+func (r Rolls) String() string {
+	jr, _ := json.Marshal(r)
+	return string(jr)
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (r *Roll) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: r.Expression, Name: "Expression"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (r *Roll) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (r *Roll) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+)
+
+// SheetEntryChange - one change to a character's sheet, numbered per character. A null old value means the entry was added,
+// a null new value that it was removed. ChangedBy is the user who made the change, or nil when the server did.
+type SheetEntryChange struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	CharacterID  uuid.UUID `json:"character_id" db:"character_id"`
+	Sequence     int       `json:"sequence" db:"sequence"`
+	SheetEntryID uuid.UUID `json:"sheet_entry_id" db:"sheet_entry_id"`
+	SkillID      uuid.UUID `json:"skill_id" db:"skill_id"`
+	OldValue     nulls.Int `json:"old_value" db:"old_value"`
+	NewValue     nulls.Int `json:"new_value" db:"new_value"`
+	ChangedBy    uuid.UUID `json:"changed_by" db:"changed_by"`
+}
+
+// BeforeUpdate - the history is append only
+func (s *SheetEntryChange) BeforeUpdate(tx *pop.Connection) error {
+	return errors.New(messages.ImmutableSheetHistoryError)
+}
+
+// BeforeDestroy - the history is append only
+func (s *SheetEntryChange) BeforeDestroy(tx *pop.Connection) error {
+	return errors.New(messages.ImmutableSheetHistoryError)
+}
+
+// String is not required by pop and may be deleted
+func (s SheetEntryChange) String() string {
+	js, _ := json.Marshal(s)
+	return string(js)
+}
+
+// SheetEntryChanges is not required by pop and may be deleted
+type SheetEntryChanges []SheetEntryChange
+
+// String is not required by pop and may be deleted
+func (s SheetEntryChanges) String() string {
+	js, _ := json.Marshal(s)
+	return string(js)
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (s *SheetEntryChange) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (s *SheetEntryChange) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (s *SheetEntryChange) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
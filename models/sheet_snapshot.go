@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+)
+
+// SheetSnapshot - a named copy of a character's whole sheet, such as "before respec", that it can be rolled back to
+type SheetSnapshot struct {
+	ID          uuid.UUID            `json:"id" db:"id"`
+	CreatedAt   time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at" db:"updated_at"`
+	CharacterID uuid.UUID            `json:"character_id" db:"character_id"`
+	Name        string               `json:"name" db:"name"`
+	CreatedBy   uuid.UUID            `json:"created_by" db:"created_by"`
+	Entries     []SheetSnapshotEntry `json:"entries" db:"-"`
+}
+
+// SheetSnapshotEntry - a skill's value and note as they were when the snapshot was taken
+type SheetSnapshotEntry struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	SnapshotID uuid.UUID `json:"snapshot_id" db:"snapshot_id"`
+	SkillID    uuid.UUID `json:"skill_id" db:"skill_id"`
+	Value      int       `json:"value" db:"value"`
+	Note       string    `json:"note" db:"note"`
+}
+
+// String is not required by pop and may be deleted
+func (s SheetSnapshot) String() string {
+	js, _ := json.Marshal(s)
+	return string(js)
+}
+
+// SheetSnapshots is not required by pop and may be deleted
+type SheetSnapshots []SheetSnapshot
+
+// String is not required by pop and may be deleted
+func (s SheetSnapshots) String() string {
+	js, _ := json.Marshal(s)
+	return string(js)
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (s *SheetSnapshot) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: s.Name, Name: "Name"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (s *SheetSnapshot) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (s *SheetSnapshot) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
@@ -2,8 +2,11 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/gobuffalo/nulls"
 	"github.com/gobuffalo/pop"
 	"github.com/gobuffalo/uuid"
 	"github.com/gobuffalo/validate"
@@ -20,8 +23,27 @@ type Skill struct {
 	ParentSkillID uuid.UUID `json:"parent_skill_id" db:"parent_skill_id"`
 	Cost          int       `json:"cost" db:"cost"`
 	StartingValue int       `json:"starting_value" db:"starting_value"`
+	MinValue      nulls.Int `json:"min_value" db:"min_value"`
+	MaxValue      nulls.Int `json:"max_value" db:"max_value"`
+	ChildRule     string    `json:"child_rule" db:"child_rule"`
+	SkillSetID    uuid.UUID `json:"skill_set_id" db:"skill_set_id"`
 }
 
+// ChildRuleNone - subskills are independent of their parent
+const ChildRuleNone = ""
+
+// ChildRuleCapped - a subskill's value may not exceed its parent's
+const ChildRuleCapped = "capped"
+
+// ChildRuleSum - the parent's value is the sum of its subskills'
+const ChildRuleSum = "sum"
+
+// ChildRuleAverage - the parent's value is the average of its subskills', rounded down
+const ChildRuleAverage = "average"
+
+// ChildRules - every rule a parent skill can declare for its subskills
+var ChildRules = []string{ChildRuleNone, ChildRuleCapped, ChildRuleSum, ChildRuleAverage}
+
 // String is not required by pop and may be deleted
 func (s Skill) String() string {
 	js, _ := json.Marshal(s)
@@ -37,13 +59,70 @@ func (s Skills) String() string {
 	return string(js)
 }
 
+// InBounds - whether a sheet value is allowed for this skill
+func (s Skill) InBounds(value int) bool {
+	return (!s.MinValue.Valid || value >= s.MinValue.Int) && (!s.MaxValue.Valid || value <= s.MaxValue.Int)
+}
+
+// Bounds - the values allowed for this skill, in words
+func (s Skill) Bounds() string {
+	switch {
+	case s.MinValue.Valid && s.MaxValue.Valid:
+		return fmt.Sprintf("between %d and %d", s.MinValue.Int, s.MaxValue.Int)
+	case s.MinValue.Valid:
+		return fmt.Sprintf("at least %d", s.MinValue.Int)
+	case s.MaxValue.Valid:
+		return fmt.Sprintf("at most %d", s.MaxValue.Int)
+	}
+	return "anything"
+}
+
+// IsDerived - whether the skill's value is worked out from its subskills rather than bought
+func (s Skill) IsDerived() bool {
+	return s.ChildRule == ChildRuleSum || s.ChildRule == ChildRuleAverage
+}
+
+// Derive - the skill's value given the total of its subskills' values and how many there are
+func (s Skill) Derive(total int, count int) int {
+	if s.ChildRule == ChildRuleAverage && count > 0 {
+		return total / count
+	}
+	return total
+}
+
+// CheckChildRule - the rule has to be one we know
+func (s *Skill) CheckChildRule() *validate.Errors {
+	verrs := validate.NewErrors()
+	for _, rule := range ChildRules {
+		if s.ChildRule == rule {
+			return verrs
+		}
+	}
+	verrs.Add("child_rule", fmt.Sprintf(messages.UnknownChildRuleError, s.ChildRule))
+	return verrs
+}
+
+// CheckBounds - the minimum can't be above the maximum, and the starting value has to be allowed
+func (s *Skill) CheckBounds() *validate.Errors {
+	verrs := validate.NewErrors()
+	if s.MinValue.Valid && s.MaxValue.Valid && s.MinValue.Int > s.MaxValue.Int {
+		verrs.Add("min_value", messages.MinAboveMaxError)
+	} else if !s.InBounds(s.StartingValue) {
+		verrs.Add("starting_value", fmt.Sprintf(messages.StartingValueOutOfBoundsError, s.Bounds()))
+	}
+	return verrs
+}
+
 // Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
 // This method is not required and may be deleted.
 func (s *Skill) Validate(tx *pop.Connection) (*validate.Errors, error) {
-	return validate.Validate(
+	verrs := validate.Validate(
 		&validators.StringIsPresent{Field: s.Name, Name: "Name"},
 		&validators.StringIsPresent{Field: s.Description, Name: "Description"},
-	), nil
+	)
+	verrs.Append(s.CheckBounds())
+	verrs.Append(s.CheckChildRule())
+	return verrs, nil
 }
 
 // ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
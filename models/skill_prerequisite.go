@@ -0,0 +1,64 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+)
+
+// SkillPrerequisite - a skill can only be raised above its starting value once another skill reaches a minimum value,
+// e.g. "Dual Wield requires One-Handed 3"
+type SkillPrerequisite struct {
+	ID              uuid.UUID `json:"id" db:"id"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	SkillID         uuid.UUID `json:"skill_id" db:"skill_id"`
+	RequiredSkillID uuid.UUID `json:"required_skill_id" db:"required_skill_id"`
+	MinimumValue    int       `json:"minimum_value" db:"minimum_value"`
+}
+
+// String is not required by pop and may be deleted
+func (s SkillPrerequisite) String() string {
+	js, _ := json.Marshal(s)
+	return string(js)
+}
+
+// SkillPrerequisites is not required by pop and may be deleted
+type SkillPrerequisites []SkillPrerequisite
+
+// String is not required by pop and may be deleted
+func (s SkillPrerequisites) String() string {
+	js, _ := json.Marshal(s)
+	return string(js)
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (s *SkillPrerequisite) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.FuncValidator{
+			Field:   s.RequiredSkillID.String(),
+			Name:    "RequiredSkillID",
+			Message: "a skill can't require itself (%s)",
+			Fn: func() bool {
+				return s.RequiredSkillID != s.SkillID
+			},
+		},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (s *SkillPrerequisite) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (s *SkillPrerequisite) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
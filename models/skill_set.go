@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+)
+
+// DefaultSkillSetID - the skill set every skill and character that existed before skill sets belongs to
+var DefaultSkillSetID = uuid.FromStringOrNil("3c5f7a2e-9d41-4b8e-a6f0-1e2d4c6b8a90")
+
+// SkillSet - a catalogue of skills for one game system; every character is bound to one
+type SkillSet struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+}
+
+// String is not required by pop and may be deleted
+func (s SkillSet) String() string {
+	js, _ := json.Marshal(s)
+	return string(js)
+}
+
+// SkillSets is not required by pop and may be deleted
+type SkillSets []SkillSet
+
+// String is not required by pop and may be deleted
+func (s SkillSets) String() string {
+	js, _ := json.Marshal(s)
+	return string(js)
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (s *SkillSet) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: s.Name, Name: "Name"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (s *SkillSet) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (s *SkillSet) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
@@ -0,0 +1,46 @@
+package models_test
+
+import (
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/nulls"
+)
+
+func (ms *ModelSuite) Test_Skill_InBounds() {
+	s := models.Skill{MinValue: nulls.NewInt(1), MaxValue: nulls.NewInt(10)}
+	ms.True(s.InBounds(1))
+	ms.True(s.InBounds(10))
+	ms.False(s.InBounds(0))
+	ms.False(s.InBounds(11))
+
+	unbounded := models.Skill{}
+	ms.True(unbounded.InBounds(9999))
+	ms.True(unbounded.InBounds(-50))
+}
+
+func (ms *ModelSuite) Test_Skill_CheckBounds() {
+	s := &models.Skill{MinValue: nulls.NewInt(5), MaxValue: nulls.NewInt(1)}
+	ms.True(s.CheckBounds().HasAny())
+
+	s = &models.Skill{MinValue: nulls.NewInt(1), MaxValue: nulls.NewInt(5), StartingValue: 0}
+	ms.True(s.CheckBounds().HasAny())
+
+	s.StartingValue = 1
+	ms.False(s.CheckBounds().HasAny())
+}
+
+func (ms *ModelSuite) Test_Skill_Derive() {
+	sum := models.Skill{ChildRule: models.ChildRuleSum}
+	ms.True(sum.IsDerived())
+	ms.Equal(9, sum.Derive(9, 2))
+
+	average := models.Skill{ChildRule: models.ChildRuleAverage}
+	ms.True(average.IsDerived())
+	ms.Equal(4, average.Derive(9, 2))
+
+	capped := models.Skill{ChildRule: models.ChildRuleCapped}
+	ms.False(capped.IsDerived())
+	ms.False(capped.CheckChildRule().HasAny())
+
+	unknown := &models.Skill{ChildRule: "product"}
+	ms.True(unknown.CheckChildRule().HasAny())
+}
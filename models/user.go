@@ -22,11 +22,32 @@ type User struct {
 
 	Email        string `json:"email" db:"email"`
 	PasswordHash string `json:"-" db:"password_hash"`
+	Role         string `json:"role" db:"role"`
 
 	Password             string `json:"-" db:"-"`
 	PasswordConfirmation string `json:"-" db:"-"`
 }
 
+// Roles a user can have
+const (
+	RolePlayer     = "player"
+	RoleGameMaster = "game_master"
+	RoleAdmin      = "admin"
+)
+
+// Roles - every role a user can have
+var Roles = []string{RolePlayer, RoleGameMaster, RoleAdmin}
+
+// HasRole - whether the user has any of the given roles
+func (u User) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if u.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
 // UserRegisterJSON - used to marshal the incoming JSON when registering a user
 type UserRegisterJSON struct {
 	Name                 string `json:"name"`
@@ -44,9 +65,29 @@ func (u *User) Create(tx *pop.Connection) (*validate.Errors, error) {
 		return validate.NewErrors(), errors.WithStack(err)
 	}
 	u.PasswordHash = string(ph)
+	if len(u.Role) == 0 {
+		u.Role = RolePlayer
+	}
 	return tx.ValidateAndCreate(u)
 }
 
+// SetPassword - replaces the user's password, as long as it is given twice the same
+func (u *User) SetPassword(password string, confirmation string) (*validate.Errors, error) {
+	verrs := validate.Validate(
+		&validators.StringIsPresent{Field: password, Name: "Password"},
+		&validators.StringsMatch{Name: "Password", Field: password, Field2: confirmation, Message: "Password does not match confirmation"},
+	)
+	if verrs.HasAny() {
+		return verrs, nil
+	}
+	ph, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return verrs, errors.WithStack(err)
+	}
+	u.PasswordHash = string(ph)
+	return verrs, nil
+}
+
 // String is not required by pop and may be deleted
 func (u User) String() string {
 	ju, _ := json.Marshal(u)
@@ -69,6 +110,7 @@ func (u *User) Validate(tx *pop.Connection) (*validate.Errors, error) {
 	return validate.Validate(
 		&validators.StringIsPresent{Field: u.Email, Name: "Email"},
 		&validators.StringIsPresent{Field: u.PasswordHash, Name: "PasswordHash"},
+		&validators.StringInclusion{Field: u.Role, Name: "Role", List: Roles},
 		// check to see if the email address is already taken:
 		&validators.FuncValidator{
 			Field:   u.Email,
@@ -78,3 +78,45 @@ func (ms *ModelSuite) Test_User_Create_UserExists() {
 	ms.NoError(err)
 	ms.Equal(1, count)
 }
+
+func (ms *ModelSuite) Test_User_Create_DefaultRole() {
+	u := &models.User{
+		Email:                "mark@example.com",
+		Password:             "password",
+		PasswordConfirmation: "password",
+	}
+
+	verrs, err := u.Create(ms.DB)
+	ms.NoError(err)
+	ms.False(verrs.HasAny())
+	ms.Equal(models.RolePlayer, u.Role)
+	ms.True(u.HasRole(models.RolePlayer, models.RoleAdmin))
+	ms.False(u.HasRole(models.RoleGameMaster, models.RoleAdmin))
+}
+
+func (ms *ModelSuite) Test_User_Create_UnknownRole() {
+	u := &models.User{
+		Email:                "mark@example.com",
+		Password:             "password",
+		PasswordConfirmation: "password",
+		Role:                 "overlord",
+	}
+
+	verrs, err := u.Create(ms.DB)
+	ms.NoError(err)
+	ms.True(verrs.HasAny())
+}
+
+func (ms *ModelSuite) Test_User_SetPassword() {
+	u := &models.User{}
+
+	verrs, err := u.SetPassword("password", "something else")
+	ms.NoError(err)
+	ms.True(verrs.HasAny())
+	ms.Zero(u.PasswordHash)
+
+	verrs, err = u.SetPassword("password", "password")
+	ms.NoError(err)
+	ms.False(verrs.HasAny())
+	ms.NotZero(u.PasswordHash)
+}
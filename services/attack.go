@@ -0,0 +1,154 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services/ruleset"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+// AttackResult - an exchange with both rolls in full and what happened to the defender's health,
+// so every client can show (and replay) exactly the same outcome
+type AttackResult struct {
+	models.Attack
+	AttackRoll   models.Roll          `json:"attack_roll"`
+	DefenseRoll  models.Roll          `json:"defense_roll"`
+	HealthChange *models.HealthChange `json:"health_change,omitempty"`
+}
+
+// ResolveAttack - rolls the attacker's offensive skill against the defender's defensive skill
+// and lets the encounter's ruleset decide whether it hits and for how much
+func ResolveAttack(tx *pop.Connection, encounter models.Encounter, attacker models.EncounterParticipant, defender models.EncounterParticipant, attackSkill models.Skill, defenseSkill models.Skill) (AttackResult, error) {
+	if err := CheckAttack(encounter, attacker, defender); err != nil {
+		return AttackResult{}, err
+	}
+
+	rs, err := GetRuleset(encounter)
+	if err != nil {
+		return AttackResult{}, err
+	}
+
+	attackValue, err := EffectiveSkillValue(tx, attacker, attackSkill.ID)
+	if err != nil {
+		return AttackResult{}, err
+	}
+	defenseValue, err := EffectiveSkillValue(tx, defender, defenseSkill.ID)
+	if err != nil {
+		return AttackResult{}, err
+	}
+
+	attackRoll, attackOutcome, err := RollCheck(tx, rs, attacker.CharacterID, attackSkill.ID, attackValue)
+	if err != nil {
+		return AttackResult{}, err
+	}
+	defenseRoll, defenseOutcome, err := RollCheck(tx, rs, defender.CharacterID, defenseSkill.ID, defenseValue)
+	if err != nil {
+		return AttackResult{}, err
+	}
+
+	attack := models.Attack{
+		EncounterID:       encounter.ID,
+		Round:             encounter.Round,
+		AttackerID:        attacker.ID,
+		DefenderID:        defender.ID,
+		AttackSkillID:     attackSkill.ID,
+		DefenseSkillID:    defenseSkill.ID,
+		AttackRollID:      attackRoll.ID,
+		DefenseRollID:     defenseRoll.ID,
+		AttackSkillValue:  attackValue,
+		DefenseSkillValue: defenseValue,
+	}
+	ScoreAttack(rs, &attack, attackOutcome, defenseOutcome)
+
+	if tx.Create(&attack) != nil {
+		return AttackResult{}, errors.New(messages.UnknownError)
+	}
+
+	// the attack goes in the log before the damage it causes
+	reason := fmt.Sprintf("%s (%d) against %s (%d)", attackSkill.Name, attackRoll.Total, defenseSkill.Name, defenseRoll.Total)
+	message := fmt.Sprintf("%s attacks %s: %s, ", ParticipantName(tx, attacker), ParticipantName(tx, defender), reason)
+	if attack.Hit {
+		message += "and hits!"
+	} else {
+		message += "and misses."
+	}
+	if _, err := LogEvent(tx, encounter.ID, models.EventAttack, attacker.ID, message, attack); err != nil {
+		return AttackResult{}, err
+	}
+
+	result := AttackResult{AttackRoll: attackRoll, DefenseRoll: defenseRoll}
+	if attack.Damage > 0 {
+		change, err := ApplyHealthChange(tx, &defender, models.HealthDamage, attack.Damage, reason)
+		if err != nil {
+			return AttackResult{}, err
+		}
+		attack.HealthChangeID = change.ID
+		result.HealthChange = &change
+		if tx.Save(&attack) != nil {
+			return AttackResult{}, errors.New(messages.UnknownError)
+		}
+	}
+
+	result.Attack = attack
+	return result, nil
+}
+
+// CheckAttack - whether the attacker may attack the defender right now
+func CheckAttack(encounter models.Encounter, attacker models.EncounterParticipant, defender models.EncounterParticipant) error {
+	if encounter.Status != models.EncounterInProgress {
+		return errors.New(messages.EncounterNotInProgressError)
+	}
+	if attacker.ID == defender.ID {
+		return errors.New(messages.CannotAttackSelfError)
+	}
+	if !attacker.CanAct() {
+		return errors.New(messages.AttackerCannotActError)
+	}
+	if defender.Status == models.ParticipantFled {
+		return errors.New(messages.DefenderFledError)
+	}
+	return nil
+}
+
+// ScoreAttack - lets the ruleset judge how the two checks of an attack went against each other
+func ScoreAttack(rs ruleset.Ruleset, attack *models.Attack, attackOutcome ruleset.Outcome, defenseOutcome ruleset.Outcome) {
+	attack.Ruleset = rs.Name()
+	attack.AttackScore = attackOutcome.Score
+	attack.AttackSuccess = attackOutcome.Success
+	attack.DefenseScore = defenseOutcome.Score
+	attack.DefenseSuccess = defenseOutcome.Success
+	attack.Margin = rs.Margin(attackOutcome, defenseOutcome)
+	attack.Hit = attack.Margin > 0
+	attack.Damage = rs.Damage(attack.Margin)
+}
+
+// GetAttackResult - loads everything that went into an attack that already happened
+func GetAttackResult(tx *pop.Connection, attack models.Attack) (AttackResult, error) {
+	result := AttackResult{Attack: attack}
+
+	var rolls []models.Roll
+	err := tx.Where("id in (?)", attack.AttackRollID, attack.DefenseRollID).All(&rolls)
+	if err != nil {
+		return result, errors.New(messages.ProblemGettingRollsError)
+	}
+	for _, roll := range rolls {
+		if roll.ID == attack.AttackRollID {
+			result.AttackRoll = roll
+		}
+		if roll.ID == attack.DefenseRollID {
+			result.DefenseRoll = roll
+		}
+	}
+
+	if attack.HealthChangeID != UUID.Nil {
+		var changes []models.HealthChange
+		if tx.Where("id = ?", attack.HealthChangeID).All(&changes) == nil && len(changes) > 0 {
+			result.HealthChange = &changes[0]
+		}
+	}
+	return result, nil
+}
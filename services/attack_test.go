@@ -0,0 +1,75 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services/ruleset"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+func Test_CheckAttack(t *testing.T) {
+	running := models.Encounter{Status: models.EncounterInProgress}
+	attacker := models.EncounterParticipant{ID: UUID.Must(UUID.NewV4()), Status: models.ParticipantActive}
+	defender := models.EncounterParticipant{ID: UUID.Must(UUID.NewV4()), Status: models.ParticipantActive}
+	downed := models.EncounterParticipant{ID: UUID.Must(UUID.NewV4()), Status: models.ParticipantDowned}
+	fled := models.EncounterParticipant{ID: UUID.Must(UUID.NewV4()), Status: models.ParticipantFled}
+
+	cases := []struct {
+		name      string
+		encounter models.Encounter
+		attacker  models.EncounterParticipant
+		defender  models.EncounterParticipant
+		err       string
+	}{
+		{"allowed", running, attacker, defender, ""},
+		{"a downed defender can still be hit", running, attacker, downed, ""},
+		{"not started", models.Encounter{Status: models.EncounterOpen}, attacker, defender, messages.EncounterNotInProgressError},
+		{"finished", models.Encounter{Status: models.EncounterFinished}, attacker, defender, messages.EncounterNotInProgressError},
+		{"themselves", running, attacker, attacker, messages.CannotAttackSelfError},
+		{"a downed attacker", running, downed, defender, messages.AttackerCannotActError},
+		{"a fled defender", running, attacker, fled, messages.DefenderFledError},
+	}
+	for _, tc := range cases {
+		err := CheckAttack(tc.encounter, tc.attacker, tc.defender)
+		if tc.err == "" && err != nil {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+		}
+		if tc.err != "" && (err == nil || err.Error() != tc.err) {
+			t.Errorf("%s: expected error %q, got %v", tc.name, tc.err, err)
+		}
+	}
+}
+
+func Test_ScoreAttack(t *testing.T) {
+	cases := []struct {
+		name    string
+		rs      ruleset.Ruleset
+		attack  ruleset.Outcome
+		defense ruleset.Outcome
+		margin  int
+		hit     bool
+		damage  int
+	}{
+		{"d20 hit", ruleset.D20{}, ruleset.Outcome{Score: 18, Success: true}, ruleset.Outcome{Score: 12, Success: true}, 6, true, 2},
+		{"d20 tie misses", ruleset.D20{}, ruleset.Outcome{Score: 12, Success: true}, ruleset.Outcome{Score: 12, Success: true}, 0, false, 0},
+		{"d20 miss", ruleset.D20{}, ruleset.Outcome{Score: 9, Success: true}, ruleset.Outcome{Score: 14, Success: true}, -5, false, 0},
+		{"d100 hit", ruleset.D100{}, ruleset.Outcome{Score: 45, Success: true}, ruleset.Outcome{Score: 5, Success: true}, 40, true, 3},
+		{"d100 failed defense", ruleset.D100{}, ruleset.Outcome{Score: 0, Success: true}, ruleset.Outcome{Score: -10, Success: false}, 1, true, 1},
+		{"d100 failed attack", ruleset.D100{}, ruleset.Outcome{Score: -3, Success: false}, ruleset.Outcome{Score: -30, Success: false}, 0, false, 0},
+	}
+	for _, tc := range cases {
+		attack := models.Attack{}
+		ScoreAttack(tc.rs, &attack, tc.attack, tc.defense)
+		if attack.Ruleset != tc.rs.Name() {
+			t.Errorf("%s: expected ruleset %s, got %s", tc.name, tc.rs.Name(), attack.Ruleset)
+		}
+		if attack.AttackScore != tc.attack.Score || attack.DefenseScore != tc.defense.Score {
+			t.Errorf("%s: scores weren't recorded", tc.name)
+		}
+		if attack.Margin != tc.margin || attack.Hit != tc.hit || attack.Damage != tc.damage {
+			t.Errorf("%s: expected margin %d, hit %t, damage %d; got %d, %t, %d", tc.name, tc.margin, tc.hit, tc.damage, attack.Margin, attack.Hit, attack.Damage)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+// BackfillSkill - gives every character of the skill's set without an entry for it one at its starting value, returning how many were created
+func BackfillSkill(tx *pop.Connection, skill models.Skill) (int, error) {
+	var characters []models.Character
+	err := tx.RawQuery("select * from characters where skill_set_id = ? and id not in (select character_id from character_sheet_entries where skill_id = ?)", skill.SkillSetID, skill.ID).All(&characters)
+	if err != nil {
+		return 0, errors.New(messages.CharacterNotFoundError)
+	}
+
+	for _, character := range characters {
+		entry := models.CharacterSheetEntry{
+			CharacterID: character.ID,
+			SkillID:     skill.ID,
+			Value:       skill.StartingValue,
+		}
+		verrs, err := tx.ValidateAndCreate(&entry)
+		if err != nil {
+			return 0, errors.New(messages.UnknownError)
+		}
+		if verrs.HasAny() {
+			return 0, errors.New(verrs.Error())
+		}
+		if err := RecordSheetChange(tx, UUID.Nil, entry, nulls.Int{}, nulls.NewInt(entry.Value)); err != nil {
+			return 0, err
+		}
+	}
+	return len(characters), nil
+}
+
+// BackfillSkills - backfills every skill, returning how many entries were created for each
+func BackfillSkills(tx *pop.Connection) (map[string]int, error) {
+	created := map[string]int{}
+	var skills []models.Skill
+	if err := tx.All(&skills); err != nil {
+		return created, errors.New(messages.ProblemGettingSkillsError)
+	}
+	for _, skill := range skills {
+		count, err := BackfillSkill(tx, skill)
+		if err != nil {
+			return created, err
+		}
+		created[skill.Name] = count
+	}
+	return created, nil
+}
+
+// MissingSkills - the skills of a character's set they have no sheet entry for
+func MissingSkills(tx *pop.Connection, character models.Character) ([]models.Skill, error) {
+	skills := []models.Skill{}
+	err := tx.RawQuery("select * from skills where skill_set_id = ? and id not in (select skill_id from character_sheet_entries where character_id = ?)", character.SkillSetID, character.ID).All(&skills)
+	if err != nil {
+		return skills, errors.New(messages.ProblemGettingSkillsError)
+	}
+	return skills, nil
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+// GetCharacter - returns a character by ID
+func GetCharacter(tx *pop.Connection, characterID UUID.UUID) (models.Character, error) {
+	var characters []models.Character
+	err := tx.Where("id = ?", characterID).All(&characters)
+	if err != nil || len(characters) == 0 {
+		return models.Character{}, errors.New(messages.CharacterNotFoundError)
+	}
+	return characters[0], nil
+}
+
+// GetCharacterByServerAndName - returns a character based on the realm they play on and their name
+func GetCharacterByServerAndName(tx *pop.Connection, server string, name string) (models.Character, error) {
+	var characters []models.Character
+	err := tx.Where("server = ?", server).Where("name = ?", name).All(&characters)
+	if err != nil || len(characters) == 0 {
+		return models.Character{}, errors.New(messages.CharacterNotFoundError)
+	}
+	return characters[0], nil
+}
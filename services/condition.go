@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+// ApplyCondition - puts a condition and its skill modifiers on a participant
+func ApplyCondition(tx *pop.Connection, participant models.EncounterParticipant, body models.Condition) (models.Condition, error) {
+	condition := models.Condition{
+		EncounterID:     participant.EncounterID,
+		ParticipantID:   participant.ID,
+		Name:            body.Name,
+		Description:     body.Description,
+		Duration:        body.Duration,
+		RemainingRounds: body.Duration,
+		Active:          true,
+	}
+
+	for _, m := range body.Modifiers {
+		if _, err := FindSkill(tx, UUID.Nil, m.SkillID, ""); err != nil {
+			return models.Condition{}, err
+		}
+	}
+
+	verrs, err := tx.ValidateAndCreate(&condition)
+	if err != nil {
+		return models.Condition{}, errors.New(messages.UnknownError)
+	}
+	if verrs.HasAny() {
+		return models.Condition{}, verrs
+	}
+
+	for _, m := range body.Modifiers {
+		modifier := models.ConditionModifier{
+			ConditionID: condition.ID,
+			SkillID:     m.SkillID,
+			Modifier:    m.Modifier,
+		}
+		if tx.Create(&modifier) != nil {
+			return models.Condition{}, errors.New(messages.UnknownError)
+		}
+		condition.Modifiers = append(condition.Modifiers, modifier)
+	}
+
+	message := fmt.Sprintf("%s is affected by %s", ParticipantName(tx, participant), condition.Name)
+	if condition.Duration > 0 {
+		message += fmt.Sprintf(" for %d rounds", condition.Duration)
+	}
+	_, err = LogEvent(tx, participant.EncounterID, models.EventConditionApplied, participant.ID, message+".", condition)
+	return condition, err
+}
+
+// GetConditions - the conditions on a participant, with their modifiers
+func GetConditions(tx *pop.Connection, participantID UUID.UUID, activeOnly bool) ([]models.Condition, error) {
+	conditions := []models.Condition{}
+	query := tx.Where("participant_id = ?", participantID)
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+	if err := query.Order("created_at asc").All(&conditions); err != nil {
+		return conditions, errors.New(messages.ProblemGettingConditionsError)
+	}
+
+	for i := range conditions {
+		conditions[i].Modifiers = []models.ConditionModifier{}
+		if err := tx.Where("condition_id = ?", conditions[i].ID).All(&conditions[i].Modifiers); err != nil {
+			return conditions, errors.New(messages.ProblemGettingConditionsError)
+		}
+	}
+	return conditions, nil
+}
+
+// GetCondition - one condition of a participant
+func GetCondition(tx *pop.Connection, participantID UUID.UUID, uuidString string) (models.Condition, error) {
+	conditions, err := GetConditions(tx, participantID, false)
+	if err != nil {
+		return models.Condition{}, err
+	}
+	for _, condition := range conditions {
+		if condition.ID.String() == uuidString {
+			return condition, nil
+		}
+	}
+	return models.Condition{}, errors.New(messages.ConditionNotFoundError)
+}
+
+// RemoveCondition - ends a condition early; it is kept, inactive, so the fight's history stays whole
+func RemoveCondition(tx *pop.Connection, condition *models.Condition) error {
+	if !condition.Active {
+		return errors.New(messages.ConditionNotActiveError)
+	}
+	condition.Active = false
+	if tx.Save(condition) != nil {
+		return errors.New(messages.UnknownError)
+	}
+
+	participant, err := GetParticipant(tx, condition.EncounterID, condition.ParticipantID.String())
+	if err != nil {
+		return err
+	}
+	return logConditionEnded(tx, participant, *condition)
+}
+
+func logConditionEnded(tx *pop.Connection, participant models.EncounterParticipant, condition models.Condition) error {
+	message := fmt.Sprintf("%s is no longer affected by %s.", ParticipantName(tx, participant), condition.Name)
+	_, err := LogEvent(tx, participant.EncounterID, models.EventConditionEnded, participant.ID, message, condition)
+	return err
+}
+
+// TickConditions - a participant's turn has come round: their timed conditions lose a round and the finished ones wear off
+func TickConditions(tx *pop.Connection, participant models.EncounterParticipant) ([]models.Condition, error) {
+	expired := []models.Condition{}
+	conditions, err := GetConditions(tx, participant.ID, true)
+	if err != nil {
+		return expired, err
+	}
+
+	for i := range conditions {
+		if conditions[i].Duration == 0 {
+			continue
+		}
+		ended := conditions[i].Tick()
+		if tx.Save(&conditions[i]) != nil {
+			return expired, errors.New(messages.UnknownError)
+		}
+		if ended {
+			expired = append(expired, conditions[i])
+			if err := logConditionEnded(tx, participant, conditions[i]); err != nil {
+				return expired, err
+			}
+		}
+	}
+	return expired, nil
+}
+
+// EffectiveSkillValue - a participant's sheet value in a skill with every active condition's modifier applied
+func EffectiveSkillValue(tx *pop.Connection, participant models.EncounterParticipant, skillID UUID.UUID) (int, error) {
+	sheetEntry, err := GetSheetEntry(tx, participant.CharacterID, skillID)
+	if err != nil {
+		return 0, err
+	}
+
+	conditions, err := GetConditions(tx, participant.ID, true)
+	if err != nil {
+		return 0, err
+	}
+
+	return sheetEntry.Value + models.Conditions(conditions).ModifierFor(skillID), nil
+}
@@ -0,0 +1,347 @@
+package dice
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxDice - the most dice a single term may roll
+const MaxDice = 100
+
+// MaxSides - the most sides a single die may have
+const MaxSides = 1000
+
+// MaxExplosions - how many times a single die may explode before we stop re-rolling it
+const MaxExplosions = 20
+
+// Term - a single part of a roll expression, either a constant or a group of dice
+type Term struct {
+	Sign     int  `json:"sign"`
+	Count    int  `json:"count"`
+	Sides    int  `json:"sides"`
+	Constant int  `json:"constant"`
+	Keep     int  `json:"keep"`
+	KeepLow  bool `json:"keep_low"`
+	Explode  bool `json:"explode"`
+}
+
+// IsConstant - whether the term is a flat number rather than dice
+func (t Term) IsConstant() bool {
+	return t.Sides == 0
+}
+
+// String - the normalised form of the term, without its sign
+func (t Term) String() string {
+	if t.IsConstant() {
+		return strconv.Itoa(t.Constant)
+	}
+	s := fmt.Sprintf("%dd%d", t.Count, t.Sides)
+	if t.Explode {
+		s += "!"
+	}
+	if t.Keep > 0 && t.Keep < t.Count {
+		if t.KeepLow {
+			s += fmt.Sprintf("kl%d", t.Keep)
+		} else {
+			s += fmt.Sprintf("kh%d", t.Keep)
+		}
+	}
+	return s
+}
+
+// Expression - a parsed roll expression such as 1d20+3
+type Expression []Term
+
+// String - the normalised form of the expression
+func (e Expression) String() string {
+	var b strings.Builder
+	for i, t := range e {
+		if t.Sign < 0 {
+			b.WriteString("-")
+		} else if i > 0 {
+			b.WriteString("+")
+		}
+		b.WriteString(t.String())
+	}
+	return b.String()
+}
+
+// Die - the outcome of one die; exploding dice keep every roll they made
+type Die struct {
+	Rolls []int `json:"rolls"`
+	Value int   `json:"value"`
+	Kept  bool  `json:"kept"`
+}
+
+// TermResult - the outcome of one term of an expression
+type TermResult struct {
+	Term  string `json:"term"`
+	Dice  []Die  `json:"dice,omitempty"`
+	Total int    `json:"total"`
+}
+
+// Result - the full breakdown of a roll
+type Result struct {
+	Expression string       `json:"expression"`
+	Terms      []TermResult `json:"terms"`
+	Modifier   int          `json:"modifier"`
+	Total      int          `json:"total"`
+}
+
+// Modify - adds a flat modifier (such as a skill value) on top of the rolled total
+func (r *Result) Modify(n int) {
+	r.Modifier += n
+	r.Total += n
+}
+
+// String - a human readable breakdown, e.g. "2d20kh1 [17, 4] + 3 = 20"
+func (r Result) String() string {
+	var b strings.Builder
+	for i, t := range r.Terms {
+		if strings.HasPrefix(t.Term, "-") {
+			b.WriteString(" - ")
+		} else if i > 0 {
+			b.WriteString(" + ")
+		}
+		b.WriteString(strings.TrimPrefix(t.Term, "-"))
+		if len(t.Dice) > 0 {
+			values := make([]string, len(t.Dice))
+			for j, d := range t.Dice {
+				values[j] = strconv.Itoa(d.Value)
+				if !d.Kept {
+					values[j] = "~" + values[j]
+				}
+			}
+			b.WriteString(" [" + strings.Join(values, ", ") + "]")
+		}
+	}
+	if r.Modifier > 0 {
+		b.WriteString(fmt.Sprintf(" + %d", r.Modifier))
+	} else if r.Modifier < 0 {
+		b.WriteString(fmt.Sprintf(" - %d", -r.Modifier))
+	}
+	b.WriteString(fmt.Sprintf(" = %d", r.Total))
+	return b.String()
+}
+
+// Parse - turns a roll expression into its terms.
+// Supported: NdM, dM, constants, + and -, kh/kl (keep highest/lowest), ! (exploding) and adv/dis (advantage/disadvantage)
+func Parse(expression string) (Expression, error) {
+	s := strings.ToLower(strings.Join(strings.Fields(expression), ""))
+	if len(s) == 0 {
+		return nil, errors.New("empty roll expression")
+	}
+
+	var terms Expression
+	pos := 0
+	for pos < len(s) {
+		sign := 1
+		if s[pos] == '+' || s[pos] == '-' {
+			if s[pos] == '-' {
+				sign = -1
+			}
+			pos++
+		} else if pos > 0 {
+			return nil, fmt.Errorf("unexpected %q at position %d", s[pos], pos)
+		}
+
+		term, next, err := parseTerm(s, pos)
+		if err != nil {
+			return nil, err
+		}
+		term.Sign = sign
+		terms = append(terms, term)
+		pos = next
+	}
+	return terms, nil
+}
+
+func parseTerm(s string, pos int) (Term, int, error) {
+	term := Term{}
+	count, next := readInt(s, pos)
+	if next < len(s) && s[next] == 'd' {
+		if next == pos {
+			count = 1
+		}
+		sides, after := readInt(s, next+1)
+		if after == next+1 {
+			if after < len(s) && s[after] == '%' {
+				sides, after = 100, after+1
+			} else {
+				return term, pos, fmt.Errorf("missing number of sides at position %d", after)
+			}
+		}
+		if count < 1 || count > MaxDice {
+			return term, pos, fmt.Errorf("dice count must be between 1 and %d", MaxDice)
+		}
+		if sides < 1 || sides > MaxSides {
+			return term, pos, fmt.Errorf("dice sides must be between 1 and %d", MaxSides)
+		}
+		term.Count = count
+		term.Sides = sides
+		return parseModifiers(s, after, term)
+	}
+	if next == pos {
+		if pos < len(s) {
+			return term, pos, fmt.Errorf("unexpected %q at position %d", s[pos], pos)
+		}
+		return term, pos, errors.New("expression ends with an operator")
+	}
+	term.Constant = count
+	return term, next, nil
+}
+
+func parseModifiers(s string, pos int, term Term) (Term, int, error) {
+	for pos < len(s) && s[pos] != '+' && s[pos] != '-' {
+		switch {
+		case s[pos] == '!':
+			if term.Sides < 2 {
+				return term, pos, errors.New("a one-sided die cannot explode")
+			}
+			term.Explode = true
+			pos++
+		case strings.HasPrefix(s[pos:], "kh"), strings.HasPrefix(s[pos:], "kl"):
+			term.KeepLow = s[pos+1] == 'l'
+			keep, next := readInt(s, pos+2)
+			if next == pos+2 {
+				keep = 1
+			}
+			if keep < 1 || keep > term.Count {
+				return term, pos, fmt.Errorf("cannot keep %d of %d dice", keep, term.Count)
+			}
+			term.Keep = keep
+			pos = next
+		case strings.HasPrefix(s[pos:], "adv"), strings.HasPrefix(s[pos:], "dis"):
+			if term.Count != 1 {
+				return term, pos, errors.New("advantage and disadvantage only apply to a single die")
+			}
+			term.Count = 2
+			term.Keep = 1
+			term.KeepLow = s[pos] == 'd'
+			pos += 3
+		default:
+			return term, pos, fmt.Errorf("unknown modifier %q at position %d", s[pos], pos)
+		}
+	}
+	return term, pos, nil
+}
+
+func readInt(s string, pos int) (int, int) {
+	end := pos
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	if end == pos {
+		return 0, pos
+	}
+	n, err := strconv.Atoi(s[pos:end])
+	if err != nil {
+		return 0, pos
+	}
+	return n, end
+}
+
+// Roller - rolls dice from its own random source so results can be reproduced from a seed
+type Roller struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewRoller - creates a roller seeded with the given value
+func NewRoller(seed int64) *Roller {
+	return &Roller{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Seed - resets the roller's random source
+func (r *Roller) Seed(seed int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rng = rand.New(rand.NewSource(seed))
+}
+
+// Roll - parses and rolls an expression
+func (r *Roller) Roll(expression string) (Result, error) {
+	e, err := Parse(expression)
+	if err != nil {
+		return Result{}, err
+	}
+	return r.Evaluate(e), nil
+}
+
+// Evaluate - rolls an already parsed expression
+func (r *Roller) Evaluate(e Expression) Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := Result{Expression: e.String()}
+	for _, term := range e {
+		tr := TermResult{Term: term.String()}
+		if term.Sign < 0 {
+			tr.Term = "-" + tr.Term
+		}
+		if term.IsConstant() {
+			tr.Total = term.Constant
+		} else {
+			tr.Dice = r.rollDice(term)
+			for _, d := range tr.Dice {
+				if d.Kept {
+					tr.Total += d.Value
+				}
+			}
+		}
+		result.Terms = append(result.Terms, tr)
+		result.Total += term.Sign * tr.Total
+	}
+	return result
+}
+
+func (r *Roller) rollDice(term Term) []Die {
+	dice := make([]Die, term.Count)
+	for i := range dice {
+		roll := r.rng.Intn(term.Sides) + 1
+		dice[i].Rolls = []int{roll}
+		dice[i].Value = roll
+		for explosions := 0; term.Explode && roll == term.Sides && explosions < MaxExplosions; explosions++ {
+			roll = r.rng.Intn(term.Sides) + 1
+			dice[i].Rolls = append(dice[i].Rolls, roll)
+			dice[i].Value += roll
+		}
+		dice[i].Kept = true
+	}
+
+	if term.Keep > 0 && term.Keep < term.Count {
+		// drop the lowest (or highest) dice one at a time, leaving the earliest of equal values kept
+		for dropped := 0; dropped < term.Count-term.Keep; dropped++ {
+			candidate := -1
+			for i, d := range dice {
+				if !d.Kept {
+					continue
+				}
+				if candidate == -1 ||
+					(!term.KeepLow && d.Value <= dice[candidate].Value) ||
+					(term.KeepLow && d.Value >= dice[candidate].Value) {
+					candidate = i
+				}
+			}
+			dice[candidate].Kept = false
+		}
+	}
+	return dice
+}
+
+var defaultRoller = NewRoller(time.Now().UnixNano())
+
+// Seed - reseeds the package level roller, mostly useful to get deterministic rolls in tests
+func Seed(seed int64) {
+	defaultRoller.Seed(seed)
+}
+
+// Roll - rolls an expression using the package level roller
+func Roll(expression string) (Result, error) {
+	return defaultRoller.Roll(expression)
+}
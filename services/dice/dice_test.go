@@ -0,0 +1,111 @@
+package dice_test
+
+import (
+	"testing"
+
+	"github.com/dosaki/emote_combat_server/services/dice"
+)
+
+func Test_Parse(t *testing.T) {
+	valid := map[string]string{
+		"1d20+3":   "1d20+3",
+		"d20 + 3":  "1d20+3",
+		"2d6kh1":   "2d6kh1",
+		"4d6kl3-1": "4d6kl3-1",
+		"1d6!":     "1d6!",
+		"1d20adv":  "2d20kh1",
+		"1d20dis":  "2d20kl1",
+		"d%":       "1d100",
+		"5":        "5",
+	}
+	for input, expected := range valid {
+		e, err := dice.Parse(input)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", input, err)
+			continue
+		}
+		if e.String() != expected {
+			t.Errorf("%q: expected %q, got %q", input, expected, e.String())
+		}
+	}
+
+	invalid := []string{"", "1d", "d", "1d20+", "2d6kh3", "1d1!", "3d20adv", "1d20x", "1000d6", "1d20++1"}
+	for _, input := range invalid {
+		if _, err := dice.Parse(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func Test_Roller_IsDeterministic(t *testing.T) {
+	first, err := dice.NewRoller(42).Roll("4d6kh3+2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := dice.NewRoller(42).Roll("4d6kh3+2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.String() != second.String() {
+		t.Errorf("expected %q and %q to match", first.String(), second.String())
+	}
+}
+
+func Test_Roller_KeepsHighest(t *testing.T) {
+	roller := dice.NewRoller(7)
+	for i := 0; i < 100; i++ {
+		result, err := roller.Roll("2d20kh1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := result.Terms[0].Dice
+		if len(d) != 2 {
+			t.Fatalf("expected 2 dice, got %d", len(d))
+		}
+		highest := d[0].Value
+		if d[1].Value > highest {
+			highest = d[1].Value
+		}
+		if result.Total != highest {
+			t.Errorf("expected %d, got %d (%s)", highest, result.Total, result)
+		}
+	}
+}
+
+func Test_Roller_Explodes(t *testing.T) {
+	roller := dice.NewRoller(1)
+	exploded := false
+	for i := 0; i < 200; i++ {
+		result, err := roller.Roll("1d4!")
+		if err != nil {
+			t.Fatal(err)
+		}
+		die := result.Terms[0].Dice[0]
+		for j, roll := range die.Rolls[:len(die.Rolls)-1] {
+			if roll != 4 {
+				t.Errorf("roll %d exploded on a %d", j, roll)
+			}
+		}
+		if len(die.Rolls) > 1 {
+			exploded = true
+		}
+		if result.Total < 1 {
+			t.Errorf("total %d is too low", result.Total)
+		}
+	}
+	if !exploded {
+		t.Error("expected at least one die to explode")
+	}
+}
+
+func Test_Result_Modify(t *testing.T) {
+	result, err := dice.NewRoller(3).Roll("1d20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rolled := result.Total
+	result.Modify(5)
+	if result.Total != rolled+5 || result.Modifier != 5 {
+		t.Errorf("expected total %d with modifier 5, got %d with %d", rolled+5, result.Total, result.Modifier)
+	}
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+// GetEncounter - returns an encounter based on its UUID
+func GetEncounter(tx *pop.Connection, uuidString string) (models.Encounter, error) {
+	uuid, err := UUID.FromString(uuidString)
+	if err != nil {
+		return models.Encounter{}, errors.New(messages.BadUUIDError)
+	}
+
+	var encounters []models.Encounter
+	err = tx.Where("id = ?", uuid).All(&encounters)
+	if err != nil || len(encounters) == 0 {
+		return models.Encounter{}, errors.New(messages.EncounterNotFoundError)
+	}
+	return encounters[0], nil
+}
+
+// GetParticipants - returns everyone in an encounter in turn order
+func GetParticipants(tx *pop.Connection, encounterID UUID.UUID) ([]models.EncounterParticipant, error) {
+	participants := []models.EncounterParticipant{}
+	err := tx.Where("encounter_id = ?", encounterID).Order("turn_order asc").All(&participants)
+	if err != nil {
+		return participants, errors.New(messages.ProblemGettingParticipantsError)
+	}
+	return participants, nil
+}
+
+// GetParticipant - returns one participant of an encounter
+func GetParticipant(tx *pop.Connection, encounterID UUID.UUID, uuidString string) (models.EncounterParticipant, error) {
+	var participants []models.EncounterParticipant
+	err := tx.Where("encounter_id = ?", encounterID).Where("id = ?", uuidString).All(&participants)
+	if err != nil || len(participants) == 0 {
+		return models.EncounterParticipant{}, errors.New(messages.ParticipantNotFoundError)
+	}
+	return participants[0], nil
+}
+
+// AddParticipant - puts a character at the end of an encounter's turn order
+func AddParticipant(tx *pop.Connection, encounter models.Encounter, characterID UUID.UUID) (models.EncounterParticipant, error) {
+	if encounter.Status == models.EncounterFinished {
+		return models.EncounterParticipant{}, errors.New(messages.EncounterFinishedError)
+	}
+
+	var characters []models.Character
+	err := tx.Where("id = ?", characterID).All(&characters)
+	if err != nil || len(characters) == 0 {
+		return models.EncounterParticipant{}, errors.New(messages.CharacterNotFoundError)
+	}
+
+	participants, err := GetParticipants(tx, encounter.ID)
+	if err != nil {
+		return models.EncounterParticipant{}, err
+	}
+	for _, p := range participants {
+		if p.CharacterID == characterID {
+			return models.EncounterParticipant{}, errors.New(messages.AlreadyParticipatingError)
+		}
+	}
+
+	participant := models.EncounterParticipant{
+		EncounterID: encounter.ID,
+		CharacterID: characterID,
+		TurnOrder:   len(participants),
+	}
+	ResetHealth(tx, encounter, &participant)
+	if tx.Create(&participant) != nil {
+		return models.EncounterParticipant{}, errors.New(messages.UnknownError)
+	}
+
+	message := fmt.Sprintf("%s joins the fight.", characters[0].Name)
+	if _, err := LogEvent(tx, encounter.ID, models.EventJoined, participant.ID, message, participant); err != nil {
+		return models.EncounterParticipant{}, err
+	}
+	return participant, nil
+}
+
+// RemoveParticipant - takes a participant out of an encounter, closing the gap in the turn order
+func RemoveParticipant(tx *pop.Connection, encounter *models.Encounter, participant models.EncounterParticipant) error {
+	if encounter.Status == models.EncounterFinished {
+		return errors.New(messages.EncounterFinishedError)
+	}
+	if tx.Destroy(&participant) != nil {
+		return errors.New(messages.UnknownError)
+	}
+
+	participants, err := GetParticipants(tx, encounter.ID)
+	if err != nil {
+		return err
+	}
+	for i := range participants {
+		if participants[i].TurnOrder != i {
+			participants[i].TurnOrder = i
+			if tx.Save(&participants[i]) != nil {
+				return errors.New(messages.UnknownError)
+			}
+		}
+	}
+
+	encounter.Leave(participant.TurnOrder, len(participants))
+	if tx.Save(encounter) != nil {
+		return errors.New(messages.UnknownError)
+	}
+
+	message := fmt.Sprintf("%s leaves the fight.", ParticipantName(tx, participant))
+	_, err = LogEvent(tx, encounter.ID, models.EventLeft, participant.ID, message, participant)
+	return err
+}
+
+// StartEncounter - rolls initiative for anyone who hasn't yet, brings everyone to full health and starts the first round
+func StartEncounter(tx *pop.Connection, encounter *models.Encounter) error {
+	if encounter.Status != models.EncounterOpen {
+		return errors.New(messages.EncounterNotOpenError)
+	}
+
+	participants, err := RollAllInitiative(tx, encounter, true)
+	if err != nil {
+		return err
+	}
+
+	if err := encounter.Start(len(participants)); err != nil {
+		return err
+	}
+
+	for i := range participants {
+		ResetHealth(tx, *encounter, &participants[i])
+		if tx.Save(&participants[i]) != nil {
+			return errors.New(messages.UnknownError)
+		}
+	}
+
+	if tx.Save(encounter) != nil {
+		return errors.New(messages.UnknownError)
+	}
+
+	message := fmt.Sprintf("The fight begins! %s acts first.", ParticipantName(tx, participants[0]))
+	_, err = LogEvent(tx, encounter.ID, models.EventStarted, participants[0].ID, message, participants)
+	return err
+}
+
+// NextTurn - hands the turn to the next participant still in the fight; their conditions lose a round
+func NextTurn(tx *pop.Connection, encounter *models.Encounter) error {
+	participants, err := GetParticipants(tx, encounter.ID)
+	if err != nil {
+		return err
+	}
+
+	round := encounter.Round
+	if err := encounter.Advance(participants); err != nil {
+		return err
+	}
+	if tx.Save(encounter) != nil {
+		return errors.New(messages.UnknownError)
+	}
+
+	current := participants[encounter.Turn]
+	message := fmt.Sprintf("It is %s's turn.", ParticipantName(tx, current))
+	if encounter.Round != round {
+		message = fmt.Sprintf("Round %d begins. %s", encounter.Round, message)
+	}
+	if _, err := LogEvent(tx, encounter.ID, models.EventTurn, current.ID, message, encounter); err != nil {
+		return err
+	}
+
+	_, err = TickConditions(tx, current)
+	return err
+}
+
+// CloseEncounter - ends the fight
+func CloseEncounter(tx *pop.Connection, encounter *models.Encounter) error {
+	if err := encounter.Close(); err != nil {
+		return err
+	}
+	if tx.Save(encounter) != nil {
+		return errors.New(messages.UnknownError)
+	}
+
+	_, err := LogEvent(tx, encounter.ID, models.EventFinished, UUID.Nil, "The fight is over.", encounter)
+	return err
+}
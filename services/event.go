@@ -0,0 +1,83 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+// LogEvent - appends an event to an encounter's log
+func LogEvent(tx *pop.Connection, encounterID UUID.UUID, kind string, participantID UUID.UUID, message string, payload interface{}) (models.EncounterEvent, error) {
+	var encounters []models.Encounter
+	if err := tx.Where("id = ?", encounterID).All(&encounters); err != nil || len(encounters) == 0 {
+		return models.EncounterEvent{}, errors.New(messages.EncounterNotFoundError)
+	}
+
+	var last []models.EncounterEvent
+	if err := tx.Where("encounter_id = ?", encounterID).Order("sequence desc").Limit(1).All(&last); err != nil {
+		return models.EncounterEvent{}, errors.New(messages.ProblemGettingEventsError)
+	}
+	sequence := 1
+	if len(last) > 0 {
+		sequence = last[0].Sequence + 1
+	}
+
+	jp, err := json.Marshal(payload)
+	if err != nil {
+		return models.EncounterEvent{}, err
+	}
+
+	event := models.EncounterEvent{
+		EncounterID:   encounterID,
+		Sequence:      sequence,
+		Round:         encounters[0].Round,
+		Kind:          kind,
+		ParticipantID: participantID,
+		Message:       message,
+		Payload:       string(jp),
+	}
+	if tx.Create(&event) != nil {
+		return models.EncounterEvent{}, errors.New(messages.UnknownError)
+	}
+	queueEvent(tx, event)
+	return event, nil
+}
+
+// GetEvents - an encounter's log in order, optionally only what came after a given sequence number
+func GetEvents(tx *pop.Connection, encounterID UUID.UUID, after int) ([]models.EncounterEvent, error) {
+	events := []models.EncounterEvent{}
+	err := tx.Where("encounter_id = ?", encounterID).Where("sequence > ?", after).Order("sequence asc").All(&events)
+	if err != nil {
+		return events, errors.New(messages.ProblemGettingEventsError)
+	}
+	return events, nil
+}
+
+// ParticipantName - the name of the character behind a participant, for log messages
+func ParticipantName(tx *pop.Connection, participant models.EncounterParticipant) string {
+	var characters []models.Character
+	if tx.Where("id = ?", participant.CharacterID).All(&characters) == nil && len(characters) > 0 {
+		return characters[0].Name
+	}
+	return "Someone"
+}
+
+// EmoteLog - the whole log as plain text, one emote-like line per event, ready to paste on a forum
+func EmoteLog(encounter models.Encounter, events []models.EncounterEvent) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s (%s)\n", encounter.Name, encounter.CreatedAt.Format("2006-01-02")))
+	for _, event := range events {
+		if event.Round > 0 {
+			b.WriteString(fmt.Sprintf("[Round %d] ", event.Round))
+		}
+		b.WriteString(event.Message)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+// ParticipantState - a participant along with what a client needs to draw them
+type ParticipantState struct {
+	models.EncounterParticipant
+	Name       string             `json:"name"`
+	Current    bool               `json:"current"`
+	Conditions []models.Condition `json:"conditions"`
+}
+
+// EncounterState - an encounter and the current state of everyone in it
+type EncounterState struct {
+	models.Encounter
+	Participants []ParticipantState `json:"participants"`
+}
+
+// MaxHealthFor - the encounter's base health plus the character's value in the health skill, if there is one
+func MaxHealthFor(tx *pop.Connection, encounter models.Encounter, characterID UUID.UUID) int {
+	maxHealth := encounter.BaseHealth
+	if encounter.HealthSkillID != UUID.Nil {
+		sheetEntry, err := GetSheetEntry(tx, characterID, encounter.HealthSkillID)
+		if err == nil {
+			maxHealth += sheetEntry.Value
+		}
+	}
+	if maxHealth < 1 {
+		maxHealth = 1
+	}
+	return maxHealth
+}
+
+// ResetHealth - brings a participant back to full health and puts them back in the fight
+func ResetHealth(tx *pop.Connection, encounter models.Encounter, participant *models.EncounterParticipant) {
+	participant.MaxHealth = MaxHealthFor(tx, encounter, participant.CharacterID)
+	participant.Health = participant.MaxHealth
+	participant.Status = models.ParticipantActive
+}
+
+// ApplyHealthChange - damages or heals a participant and records why.
+// Health never goes below 0 or above the maximum; reaching 0 downs an active participant and healing a downed one brings them back.
+func ApplyHealthChange(tx *pop.Connection, participant *models.EncounterParticipant, kind string, amount int, reason string) (models.HealthChange, error) {
+	status := participant.Status
+	if err := ChangeHealth(participant, kind, amount); err != nil {
+		return models.HealthChange{}, err
+	}
+
+	change, err := saveHealthChange(tx, participant, kind, amount, reason)
+	if err != nil {
+		return change, err
+	}
+
+	name := ParticipantName(tx, *participant)
+	event := models.EventDamage
+	message := fmt.Sprintf("%s takes %d damage", name, amount)
+	if kind == models.HealthHeal {
+		event = models.EventHeal
+		message = fmt.Sprintf("%s is healed for %d", name, amount)
+	}
+	if len(reason) > 0 {
+		message += fmt.Sprintf(" (%s)", reason)
+	}
+	message += fmt.Sprintf(" and is at %d/%d health.", participant.Health, participant.MaxHealth)
+	if participant.Status != status {
+		if participant.Status == models.ParticipantDowned {
+			message += fmt.Sprintf(" %s goes down!", name)
+		} else {
+			message += fmt.Sprintf(" %s is back on their feet.", name)
+		}
+	}
+	_, err = LogEvent(tx, participant.EncounterID, event, participant.ID, message, change)
+	return change, err
+}
+
+// ChangeHealth - damages or heals a participant without saving anything; see ApplyHealthChange
+func ChangeHealth(participant *models.EncounterParticipant, kind string, amount int) error {
+	if amount < 0 {
+		return errors.New(messages.NegativeAmountError)
+	}
+
+	switch kind {
+	case models.HealthDamage:
+		participant.Health -= amount
+		if participant.Health <= 0 {
+			participant.Health = 0
+			if participant.Status == models.ParticipantActive {
+				participant.Status = models.ParticipantDowned
+			}
+		}
+	case models.HealthHeal:
+		participant.Health += amount
+		if participant.Health > participant.MaxHealth {
+			participant.Health = participant.MaxHealth
+		}
+		if participant.Health > 0 && participant.Status == models.ParticipantDowned {
+			participant.Status = models.ParticipantActive
+		}
+	default:
+		return errors.New(messages.UnknownError)
+	}
+	return nil
+}
+
+// SetStatus - changes a participant's status by hand, e.g. when they flee
+func SetStatus(tx *pop.Connection, participant *models.EncounterParticipant, status string, reason string) (models.HealthChange, error) {
+	valid := false
+	for _, s := range models.ParticipantStatuses {
+		if s == status {
+			valid = true
+		}
+	}
+	if !valid {
+		return models.HealthChange{}, errors.New(messages.UnknownStatusError)
+	}
+
+	participant.Status = status
+	change, err := saveHealthChange(tx, participant, models.HealthStatus, 0, reason)
+	if err != nil {
+		return change, err
+	}
+
+	message := fmt.Sprintf("%s is now %s", ParticipantName(tx, *participant), status)
+	if len(reason) > 0 {
+		message += fmt.Sprintf(" (%s)", reason)
+	}
+	_, err = LogEvent(tx, participant.EncounterID, models.EventStatus, participant.ID, message+".", change)
+	return change, err
+}
+
+func saveHealthChange(tx *pop.Connection, participant *models.EncounterParticipant, kind string, amount int, reason string) (models.HealthChange, error) {
+	if tx.Save(participant) != nil {
+		return models.HealthChange{}, errors.New(messages.UnknownError)
+	}
+
+	change := models.HealthChange{
+		EncounterID:   participant.EncounterID,
+		ParticipantID: participant.ID,
+		Kind:          kind,
+		Amount:        amount,
+		Reason:        reason,
+		Health:        participant.Health,
+		Status:        participant.Status,
+	}
+	if tx.Create(&change) != nil {
+		return models.HealthChange{}, errors.New(messages.UnknownError)
+	}
+	return change, nil
+}
+
+// GetEncounterState - the encounter with every participant's name, health and status, in turn order
+func GetEncounterState(tx *pop.Connection, encounter models.Encounter) (EncounterState, error) {
+	state := EncounterState{Encounter: encounter, Participants: []ParticipantState{}}
+
+	participants, err := GetParticipants(tx, encounter.ID)
+	if err != nil {
+		return state, err
+	}
+
+	for i, p := range participants {
+		participantState := ParticipantState{
+			EncounterParticipant: p,
+			Current:              encounter.Status == models.EncounterInProgress && i == encounter.Turn,
+		}
+		var characters []models.Character
+		if tx.Where("id = ?", p.CharacterID).All(&characters) == nil && len(characters) > 0 {
+			participantState.Name = characters[0].Name
+		}
+		participantState.Conditions, err = GetConditions(tx, p.ID, true)
+		if err != nil {
+			return state, err
+		}
+		state.Participants = append(state.Participants, participantState)
+	}
+	return state, nil
+}
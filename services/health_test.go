@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+)
+
+func Test_ChangeHealth(t *testing.T) {
+	cases := []struct {
+		name   string
+		status string
+		health int
+		kind   string
+		amount int
+		want   int
+		after  string
+		err    string
+	}{
+		{"damage", models.ParticipantActive, 10, models.HealthDamage, 3, 7, models.ParticipantActive, ""},
+		{"damage down to 0 downs", models.ParticipantActive, 3, models.HealthDamage, 3, 0, models.ParticipantDowned, ""},
+		{"damage never goes below 0", models.ParticipantActive, 3, models.HealthDamage, 8, 0, models.ParticipantDowned, ""},
+		{"damage doesn't bring the fled back", models.ParticipantFled, 3, models.HealthDamage, 8, 0, models.ParticipantFled, ""},
+		{"heal", models.ParticipantActive, 4, models.HealthHeal, 3, 7, models.ParticipantActive, ""},
+		{"heal never goes above the maximum", models.ParticipantActive, 8, models.HealthHeal, 5, 10, models.ParticipantActive, ""},
+		{"heal brings the downed back", models.ParticipantDowned, 0, models.HealthHeal, 2, 2, models.ParticipantActive, ""},
+		{"heal by 0 leaves the downed down", models.ParticipantDowned, 0, models.HealthHeal, 0, 0, models.ParticipantDowned, ""},
+		{"heal doesn't bring the fled back", models.ParticipantFled, 2, models.HealthHeal, 2, 4, models.ParticipantFled, ""},
+		{"negative amount", models.ParticipantActive, 5, models.HealthDamage, -2, 5, models.ParticipantActive, messages.NegativeAmountError},
+		{"unknown kind", models.ParticipantActive, 5, "drain", 2, 5, models.ParticipantActive, messages.UnknownError},
+	}
+	for _, tc := range cases {
+		participant := models.EncounterParticipant{Status: tc.status, Health: tc.health, MaxHealth: 10}
+		err := ChangeHealth(&participant, tc.kind, tc.amount)
+		if tc.err != "" && (err == nil || err.Error() != tc.err) {
+			t.Errorf("%s: expected error %q, got %v", tc.name, tc.err, err)
+		}
+		if tc.err == "" && err != nil {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+		}
+		if participant.Health != tc.want || participant.Status != tc.after {
+			t.Errorf("%s: expected %d health and %s, got %d and %s", tc.name, tc.want, tc.after, participant.Health, participant.Status)
+		}
+	}
+}
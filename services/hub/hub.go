@@ -0,0 +1,140 @@
+package hub
+
+import (
+	"encoding/json"
+	"sync"
+
+	UUID "github.com/gobuffalo/uuid"
+)
+
+// SubscriberBuffer - how many messages a subscriber may fall behind before it gets dropped
+const SubscriberBuffer = 64
+
+// Message - something pushed to everyone watching an encounter.
+// ID is the event's sequence number in the encounter log, or 0 for messages that aren't logged (e.g. state snapshots)
+type Message struct {
+	ID   int             `json:"id,omitempty"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// NewMessage - encodes data once so it can be sent to every subscriber as is
+func NewMessage(id int, kind string, data interface{}) (Message, error) {
+	jd, err := json.Marshal(data)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{ID: id, Type: kind, Data: jd}, nil
+}
+
+// Subscriber - one connected client. Messages is closed when the subscriber is dropped or unsubscribes
+type Subscriber struct {
+	EncounterID UUID.UUID
+	Messages    chan Message
+}
+
+// Hub - the subscribers of a single encounter
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[*Subscriber]bool
+}
+
+func (h *Hub) add(s *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subscribers[s] = true
+}
+
+// remove - takes a subscriber out and closes its channel, returning how many are left
+func (h *Hub) remove(s *Subscriber) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subscribers[s] {
+		delete(h.subscribers, s)
+		close(s.Messages)
+	}
+	return len(h.subscribers)
+}
+
+// broadcast - sends a message to every subscriber without waiting on any of them; those whose buffer is full are dropped
+func (h *Hub) broadcast(m Message) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for s := range h.subscribers {
+		select {
+		case s.Messages <- m:
+		default:
+			delete(h.subscribers, s)
+			close(s.Messages)
+		}
+	}
+	return len(h.subscribers)
+}
+
+// Broker - keeps one hub per encounter that has subscribers, and gets rid of it when the last one leaves
+type Broker struct {
+	mu   sync.Mutex
+	hubs map[UUID.UUID]*Hub
+}
+
+// New - creates an empty broker
+func New() *Broker {
+	return &Broker{hubs: map[UUID.UUID]*Hub{}}
+}
+
+// Subscribe - starts listening to an encounter
+func (b *Broker) Subscribe(encounterID UUID.UUID) *Subscriber {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	h, ok := b.hubs[encounterID]
+	if !ok {
+		h = &Hub{subscribers: map[*Subscriber]bool{}}
+		b.hubs[encounterID] = h
+	}
+	s := &Subscriber{EncounterID: encounterID, Messages: make(chan Message, SubscriberBuffer)}
+	h.add(s)
+	return s
+}
+
+// Unsubscribe - stops listening; safe to call more than once or after the subscriber was dropped
+func (b *Broker) Unsubscribe(s *Subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	h, ok := b.hubs[s.EncounterID]
+	if !ok {
+		return
+	}
+	if h.remove(s) == 0 {
+		delete(b.hubs, s.EncounterID)
+	}
+}
+
+// Publish - pushes a message to everyone listening to an encounter
+func (b *Broker) Publish(encounterID UUID.UUID, m Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	h, ok := b.hubs[encounterID]
+	if !ok {
+		return
+	}
+	if h.broadcast(m) == 0 {
+		delete(b.hubs, encounterID)
+	}
+}
+
+// Subscribers - how many clients are listening to an encounter
+func (b *Broker) Subscribers(encounterID UUID.UUID) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	h, ok := b.hubs[encounterID]
+	if !ok {
+		return 0
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers)
+}
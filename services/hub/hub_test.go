@@ -0,0 +1,73 @@
+package hub_test
+
+import (
+	"testing"
+
+	"github.com/dosaki/emote_combat_server/services/hub"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+func Test_Broker_Publish(t *testing.T) {
+	b := hub.New()
+	encounter, _ := UUID.NewV4()
+	other, _ := UUID.NewV4()
+
+	first := b.Subscribe(encounter)
+	second := b.Subscribe(encounter)
+	elsewhere := b.Subscribe(other)
+
+	m, err := hub.NewMessage(1, "event", map[string]string{"kind": "turn"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Publish(encounter, m)
+
+	for _, s := range []*hub.Subscriber{first, second} {
+		got := <-s.Messages
+		if got.ID != 1 || string(got.Data) != `{"kind":"turn"}` {
+			t.Errorf("unexpected message %+v", got)
+		}
+	}
+	if len(elsewhere.Messages) != 0 {
+		t.Error("a subscriber got a message for another encounter")
+	}
+}
+
+func Test_Broker_Unsubscribe(t *testing.T) {
+	b := hub.New()
+	encounter, _ := UUID.NewV4()
+
+	s := b.Subscribe(encounter)
+	b.Unsubscribe(s)
+	b.Unsubscribe(s)
+
+	if _, open := <-s.Messages; open {
+		t.Error("expected the subscriber's channel to be closed")
+	}
+	if b.Subscribers(encounter) != 0 {
+		t.Errorf("expected no subscribers, got %d", b.Subscribers(encounter))
+	}
+}
+
+func Test_Broker_DropsSlowSubscribers(t *testing.T) {
+	b := hub.New()
+	encounter, _ := UUID.NewV4()
+
+	slow := b.Subscribe(encounter)
+	m, _ := hub.NewMessage(0, "state", nil)
+	for i := 0; i <= hub.SubscriberBuffer; i++ {
+		b.Publish(encounter, m)
+	}
+
+	if b.Subscribers(encounter) != 0 {
+		t.Error("expected the slow subscriber to be dropped")
+	}
+	received := 0
+	for range slow.Messages {
+		received++
+	}
+	if received != hub.SubscriberBuffer {
+		t.Errorf("expected %d buffered messages, got %d", hub.SubscriberBuffer, received)
+	}
+	b.Unsubscribe(slow)
+}
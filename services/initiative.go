@@ -0,0 +1,223 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+// RollInitiative - rolls initiative for one participant with the encounter's ruleset, using its initiative skill if it has one
+func RollInitiative(tx *pop.Connection, encounter models.Encounter, participant *models.EncounterParticipant) error {
+	rs, err := GetRuleset(encounter)
+	if err != nil {
+		return err
+	}
+
+	// someone without the initiative skill on their sheet still rolls, without it, and the log says so
+	skillValue := 0
+	missingSkill := ""
+	if encounter.InitiativeSkillID != UUID.Nil {
+		value, err := EffectiveSkillValue(tx, *participant, encounter.InitiativeSkillID)
+		if err != nil && err.Error() != messages.SheetNotFoundError {
+			return err
+		}
+		if err != nil {
+			skill, serr := FindSkill(tx, UUID.Nil, encounter.InitiativeSkillID, "")
+			if serr != nil {
+				return serr
+			}
+			missingSkill = skill.Name
+		}
+		skillValue = value
+	}
+
+	roll, outcome, err := RollCheck(tx, rs, participant.CharacterID, encounter.InitiativeSkillID, skillValue)
+	if err != nil {
+		return err
+	}
+
+	participant.Initiative = outcome.Score
+	participant.InitiativeModifier = skillValue
+	participant.InitiativeRollID = roll.ID
+	participant.Delayed = false
+	if tx.Save(participant) != nil {
+		return errors.New(messages.UnknownError)
+	}
+
+	message := fmt.Sprintf("%s rolls initiative: %s.", ParticipantName(tx, *participant), roll.Detail)
+	if len(missingSkill) > 0 {
+		message += fmt.Sprintf(" They have no %s on their sheet, so they roll without it.", missingSkill)
+	}
+	_, err = LogEvent(tx, encounter.ID, models.EventInitiative, participant.ID, message, roll)
+	return err
+}
+
+// RollAllInitiative - rolls initiative for every participant (or only those who haven't rolled yet) and sorts the encounter by it
+func RollAllInitiative(tx *pop.Connection, encounter *models.Encounter, onlyMissing bool) ([]models.EncounterParticipant, error) {
+	participants, err := GetParticipants(tx, encounter.ID)
+	if err != nil {
+		return participants, err
+	}
+
+	for i := range participants {
+		if onlyMissing && participants[i].HasRolledInitiative() {
+			continue
+		}
+		if err := RollInitiative(tx, *encounter, &participants[i]); err != nil {
+			return participants, err
+		}
+	}
+	return SortByInitiative(tx, encounter, participants)
+}
+
+// SortByInitiative - puts the participants in initiative order, keeping the turn with whoever currently has it
+func SortByInitiative(tx *pop.Connection, encounter *models.Encounter, participants []models.EncounterParticipant) ([]models.EncounterParticipant, error) {
+	ordered := make([]models.EncounterParticipant, len(participants))
+	copy(ordered, participants)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].GoesBefore(ordered[j])
+	})
+	return ordered, ApplyTurnOrder(tx, encounter, participants, ordered)
+}
+
+// ApplyTurnOrder - saves a new turn order, keeping the turn with whoever had it in the previous order
+func ApplyTurnOrder(tx *pop.Connection, encounter *models.Encounter, previous []models.EncounterParticipant, ordered []models.EncounterParticipant) error {
+	current := UUID.Nil
+	if encounter.Turn < len(previous) {
+		current = previous[encounter.Turn].ID
+	}
+
+	for i := range ordered {
+		if ordered[i].TurnOrder != i {
+			ordered[i].TurnOrder = i
+			if tx.Save(&ordered[i]) != nil {
+				return errors.New(messages.UnknownError)
+			}
+		}
+		if ordered[i].ID == current {
+			encounter.Turn = i
+		}
+	}
+
+	if tx.Save(encounter) != nil {
+		return errors.New(messages.UnknownError)
+	}
+	return nil
+}
+
+// ReorderParticipants - lets the host set the turn order by hand; every participant must be listed exactly once
+func ReorderParticipants(tx *pop.Connection, encounter *models.Encounter, order []UUID.UUID) ([]models.EncounterParticipant, error) {
+	participants, err := GetParticipants(tx, encounter.ID)
+	if err != nil {
+		return participants, err
+	}
+	if len(order) != len(participants) {
+		return participants, errors.New(messages.IncompleteTurnOrderError)
+	}
+
+	byID := map[UUID.UUID]models.EncounterParticipant{}
+	for _, p := range participants {
+		byID[p.ID] = p
+	}
+
+	ordered := []models.EncounterParticipant{}
+	for _, id := range order {
+		p, ok := byID[id]
+		if !ok {
+			return participants, errors.New(messages.IncompleteTurnOrderError)
+		}
+		delete(byID, id)
+		ordered = append(ordered, p)
+	}
+	if err := ApplyTurnOrder(tx, encounter, participants, ordered); err != nil {
+		return ordered, err
+	}
+	return ordered, logTurnOrder(tx, *encounter, UUID.Nil, "The turn order changes", ordered)
+}
+
+// DelayParticipant - moves a participant to act right after another one, or last if no one is given.
+// If it was their turn, the turn passes to whoever now sits in their old place.
+func DelayParticipant(tx *pop.Connection, encounter *models.Encounter, participantID UUID.UUID, after UUID.UUID) ([]models.EncounterParticipant, error) {
+	participants, err := GetParticipants(tx, encounter.ID)
+	if err != nil {
+		return participants, err
+	}
+
+	ordered, position, err := DelayedOrder(participants, participantID, after)
+	if err != nil {
+		return participants, err
+	}
+	delayed := ordered[position]
+
+	wasCurrent := encounter.Turn < len(participants) && participants[encounter.Turn].ID == participantID
+	turn := encounter.Turn
+	if err := ApplyTurnOrder(tx, encounter, participants, ordered); err != nil {
+		return ordered, err
+	}
+	if wasCurrent {
+		encounter.Turn = turn % len(ordered)
+		if tx.Save(encounter) != nil {
+			return ordered, errors.New(messages.UnknownError)
+		}
+	}
+	// ApplyTurnOrder only saves participants whose position changed
+	if tx.Save(&ordered[position]) != nil {
+		return ordered, errors.New(messages.UnknownError)
+	}
+
+	message := fmt.Sprintf("%s delays their turn", ParticipantName(tx, delayed))
+	return ordered, logTurnOrder(tx, *encounter, delayed.ID, message, ordered)
+}
+
+// DelayedOrder - the turn order with a participant moved to right after another one, or last if no one is given;
+// also returns where the delayed participant ends up
+func DelayedOrder(participants []models.EncounterParticipant, participantID UUID.UUID, after UUID.UUID) ([]models.EncounterParticipant, int, error) {
+	var delayed *models.EncounterParticipant
+	ordered := []models.EncounterParticipant{}
+	for i := range participants {
+		if participants[i].ID == participantID {
+			delayed = &participants[i]
+			continue
+		}
+		ordered = append(ordered, participants[i])
+	}
+	if delayed == nil {
+		return participants, -1, errors.New(messages.ParticipantNotFoundError)
+	}
+	if after == participantID {
+		return participants, -1, errors.New(messages.CannotDelayAfterSelfError)
+	}
+
+	delayed.Delayed = true
+	position := len(ordered)
+	if after != UUID.Nil {
+		position = -1
+		for i := range ordered {
+			if ordered[i].ID == after {
+				position = i + 1
+			}
+		}
+		if position == -1 {
+			return participants, -1, errors.New(messages.ParticipantNotFoundError)
+		}
+	}
+	ordered = append(ordered[:position], append([]models.EncounterParticipant{*delayed}, ordered[position:]...)...)
+
+	return ordered, position, nil
+}
+
+func logTurnOrder(tx *pop.Connection, encounter models.Encounter, participantID UUID.UUID, message string, ordered []models.EncounterParticipant) error {
+	names := make([]string, len(ordered))
+	for i, p := range ordered {
+		names[i] = ParticipantName(tx, p)
+	}
+	message = fmt.Sprintf("%s: %s.", message, strings.Join(names, ", "))
+	_, err := LogEvent(tx, encounter.ID, models.EventTurnOrder, participantID, message, ordered)
+	return err
+}
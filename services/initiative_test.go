@@ -0,0 +1,59 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+func Test_DelayedOrder(t *testing.T) {
+	participants := make([]models.EncounterParticipant, 3)
+	for i := range participants {
+		participants[i] = models.EncounterParticipant{ID: UUID.Must(UUID.NewV4()), TurnOrder: i}
+	}
+	a, b, c := participants[0].ID, participants[1].ID, participants[2].ID
+
+	cases := []struct {
+		name     string
+		delayed  UUID.UUID
+		after    UUID.UUID
+		order    []UUID.UUID
+		position int
+		err      string
+	}{
+		{"to the end", a, UUID.Nil, []UUID.UUID{b, c, a}, 2, ""},
+		{"after someone", a, b, []UUID.UUID{b, a, c}, 1, ""},
+		{"after the last", b, c, []UUID.UUID{a, c, b}, 2, ""},
+		{"the last to the end", c, UUID.Nil, []UUID.UUID{a, b, c}, 2, ""},
+		{"after themselves", a, a, nil, -1, messages.CannotDelayAfterSelfError},
+		{"unknown participant", UUID.Must(UUID.NewV4()), UUID.Nil, nil, -1, messages.ParticipantNotFoundError},
+		{"after someone unknown", a, UUID.Must(UUID.NewV4()), nil, -1, messages.ParticipantNotFoundError},
+	}
+	for _, tc := range cases {
+		given := append([]models.EncounterParticipant{}, participants...)
+		ordered, position, err := DelayedOrder(given, tc.delayed, tc.after)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("%s: expected error %q, got %v", tc.name, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+			continue
+		}
+		if position != tc.position {
+			t.Errorf("%s: expected position %d, got %d", tc.name, tc.position, position)
+		}
+		if !ordered[position].Delayed {
+			t.Errorf("%s: the delayed participant isn't marked as delayed", tc.name)
+		}
+		for i, id := range tc.order {
+			if ordered[i].ID != id {
+				t.Errorf("%s: expected %s at %d, got %s", tc.name, id, i, ordered[i].ID)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package keys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"math/big"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// JWK - a public key as described by RFC 7517
+type JWK struct {
+	KeyType string `json:"kty"`
+	ID      string `json:"kid"`
+	Use     string `json:"use"`
+	Alg     string `json:"alg"`
+	N       string `json:"n,omitempty"`
+	E       string `json:"e,omitempty"`
+	Curve   string `json:"crv,omitempty"`
+	X       string `json:"x,omitempty"`
+}
+
+// JWKSet - the public keys other services can check our tokens with
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS - every public key tokens are accepted from. HMAC secrets are never published
+func (k *Keyring) JWKS() (JWKSet, error) {
+	set := JWKSet{Keys: []JWK{}}
+	keys, err := k.Keys()
+	if err != nil {
+		return set, err
+	}
+	for _, key := range keys {
+		switch public := key.Public.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KeyType: "RSA",
+				ID:      key.ID,
+				Use:     "sig",
+				Alg:     key.Method.Alg(),
+				N:       jwt.EncodeSegment(public.N.Bytes()),
+				E:       jwt.EncodeSegment(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KeyType: "OKP",
+				ID:      key.ID,
+				Use:     "sig",
+				Alg:     key.Method.Alg(),
+				Curve:   "Ed25519",
+				X:       jwt.EncodeSegment(public),
+			})
+		}
+	}
+	return set, nil
+}
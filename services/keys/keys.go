@@ -0,0 +1,299 @@
+package keys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// DefaultCheckInterval - how often key files are checked for changes
+const DefaultCheckInterval = 5 * time.Second
+
+// Key - a key tokens are signed or verified with.
+// Verification only keys have no private part; HMAC keys use the same secret for both
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private interface{}
+	Public  interface{}
+}
+
+// Config - where the keys live. The signing key can be an RSA or Ed25519 private key in PEM,
+// or anything else for an HMAC secret. Verification keys are extra public (or private) keys whose tokens
+// are still accepted, such as the previous signing key while its tokens run out. Their paths can be
+// prefixed with "kid=" to give them an ID, otherwise the ID is worked out from the key itself
+type Config struct {
+	SigningKeyPath       string
+	SigningKeyID         string
+	VerificationKeyPaths []string
+	CheckInterval        time.Duration
+}
+
+// Keyring - the keys in memory, reloaded whenever one of their files changes. Safe to share between requests
+type Keyring struct {
+	config Config
+
+	mu        sync.RWMutex
+	signing   *Key
+	keys      map[string]*Key
+	modTimes  map[string]time.Time
+	lastCheck time.Time
+}
+
+// New - a keyring for the given config. Nothing is read until a key is needed
+func New(config Config) *Keyring {
+	if config.CheckInterval == 0 {
+		config.CheckInterval = DefaultCheckInterval
+	}
+	return &Keyring{config: config}
+}
+
+// SplitPaths - turns a comma separated list of key paths into a slice
+func SplitPaths(paths string) []string {
+	split := []string{}
+	for _, path := range strings.Split(paths, ",") {
+		if path = strings.TrimSpace(path); len(path) > 0 {
+			split = append(split, path)
+		}
+	}
+	return split
+}
+
+// SigningKey - the key new tokens are signed with
+func (k *Keyring) SigningKey() (*Key, error) {
+	if err := k.refresh(); err != nil {
+		return nil, err
+	}
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.signing, nil
+}
+
+// VerificationKey - the key with the given ID; tokens without an ID are checked against the signing key
+func (k *Keyring) VerificationKey(id string) (*Key, error) {
+	if err := k.refresh(); err != nil {
+		return nil, err
+	}
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if len(id) == 0 {
+		return k.signing, nil
+	}
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", id)
+	}
+	return key, nil
+}
+
+// Sign - signs claims with the signing key, naming it in the token's kid header
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	key, err := k.SigningKey()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+// Keyfunc - for jwt.Parse: picks the key named by the token, and refuses tokens that claim another algorithm than their key's
+func (k *Keyring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, err := k.VerificationKey(kid)
+	if err != nil {
+		return nil, err
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.Public, nil
+}
+
+// Keys - every key tokens are accepted from, the signing key first
+func (k *Keyring) Keys() ([]*Key, error) {
+	if err := k.refresh(); err != nil {
+		return nil, err
+	}
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	ids := []string{}
+	for id := range k.keys {
+		if id != k.signing.ID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	keys := []*Key{k.signing}
+	for _, id := range ids {
+		keys = append(keys, k.keys[id])
+	}
+	return keys, nil
+}
+
+// refresh - loads the keys the first time and again whenever a file has changed since.
+// If a reload fails the keys already in memory are kept, so a half written file doesn't lock everyone out
+func (k *Keyring) refresh() error {
+	k.mu.RLock()
+	loaded := k.signing != nil
+	fresh := loaded && time.Since(k.lastCheck) < k.config.CheckInterval
+	k.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.signing != nil && time.Since(k.lastCheck) < k.config.CheckInterval {
+		return nil
+	}
+	k.lastCheck = time.Now()
+
+	modTimes := map[string]time.Time{}
+	changed := k.signing == nil
+	for _, path := range k.paths() {
+		info, err := os.Stat(path)
+		if err != nil {
+			if k.signing == nil {
+				return fmt.Errorf("could not open jwt key, %v", err)
+			}
+			log.Println("could not check jwt key", err)
+			return nil
+		}
+		modTimes[path] = info.ModTime()
+		if !info.ModTime().Equal(k.modTimes[path]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	signing, keys, err := k.load()
+	if err != nil {
+		if k.signing == nil {
+			return err
+		}
+		log.Println("could not reload jwt keys, keeping the old ones", err)
+		return nil
+	}
+	k.signing, k.keys, k.modTimes = signing, keys, modTimes
+	return nil
+}
+
+func (k *Keyring) paths() []string {
+	paths := []string{k.config.SigningKeyPath}
+	for _, path := range k.config.VerificationKeyPaths {
+		_, path = splitID(path)
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func (k *Keyring) load() (*Key, map[string]*Key, error) {
+	data, err := ioutil.ReadFile(k.config.SigningKeyPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not open jwt key, %v", err)
+	}
+	signing, err := ParseKey(data, k.config.SigningKeyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if signing.Private == nil {
+		return nil, nil, errors.New("the jwt signing key has to be a private key or a secret")
+	}
+
+	keys := map[string]*Key{signing.ID: signing}
+	for _, path := range k.config.VerificationKeyPaths {
+		id, path := splitID(path)
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not open jwt verification key, %v", err)
+		}
+		key, err := ParseKey(data, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		key.Private = nil
+		keys[key.ID] = key
+	}
+	return signing, keys, nil
+}
+
+func splitID(path string) (string, string) {
+	if i := strings.Index(path, "="); i > 0 {
+		return path[:i], path[i+1:]
+	}
+	return "", path
+}
+
+// ParseKey - reads an RSA or Ed25519 key from PEM, or takes anything that isn't PEM as an HMAC secret.
+// Without an ID the key gets one from a hash of its public part
+func ParseKey(data []byte, id string) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		sum := sha256.Sum256(data)
+		return keyWithID(&Key{Method: jwt.SigningMethodHS256, Private: data, Public: data}, id, sum[:]), nil
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported jwt key type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse jwt key, %v", err)
+	}
+
+	key := &Key{}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.Private, key.Public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.Private, key.Public = SigningMethodEdDSA, k, k.Public().(ed25519.PublicKey)
+	case ed25519.PublicKey:
+		key.Method, key.Public = SigningMethodEdDSA, k
+	default:
+		return nil, errors.New("jwt keys have to be RSA or Ed25519")
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(key.Public)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse jwt key, %v", err)
+	}
+	sum := sha256.Sum256(der)
+	return keyWithID(key, id, sum[:]), nil
+}
+
+func keyWithID(key *Key, id string, sum []byte) *Key {
+	key.ID = id
+	if len(key.ID) == 0 {
+		key.ID = hex.EncodeToString(sum)[:16]
+	}
+	return key
+}
@@ -0,0 +1,186 @@
+package keys_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/dosaki/emote_combat_server/services/keys"
+)
+
+func writeRSAKey(t *testing.T, path string) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func writeEd25519Key(t *testing.T, path string) ed25519.PrivateKey {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func claims() jwt.StandardClaims {
+	return jwt.StandardClaims{Id: "someone", ExpiresAt: time.Now().Add(time.Minute).Unix()}
+}
+
+func Test_Keyring_SignAndParse(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	for name, write := range map[string]func(*testing.T, string){
+		"rsa":     func(t *testing.T, path string) { writeRSAKey(t, path) },
+		"ed25519": func(t *testing.T, path string) { writeEd25519Key(t, path) },
+		"hmac": func(t *testing.T, path string) {
+			ioutil.WriteFile(path, []byte("a secret"), 0600)
+		},
+	} {
+		path := filepath.Join(dir, name+".key")
+		write(t, path)
+		k := keys.New(keys.Config{SigningKeyPath: path})
+
+		tokenString, err := k.Sign(claims())
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		token, err := jwt.Parse(tokenString, k.Keyfunc)
+		if err != nil || !token.Valid {
+			t.Errorf("%s: a token we signed doesn't verify: %v", name, err)
+			continue
+		}
+		signing, _ := k.SigningKey()
+		if token.Header["kid"] != signing.ID {
+			t.Errorf("%s: expected kid %s, got %v", name, signing.ID, token.Header["kid"])
+		}
+	}
+}
+
+func Test_Keyring_Rotation(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	oldPath := filepath.Join(dir, "old.key")
+	newPath := filepath.Join(dir, "new.key")
+	writeRSAKey(t, oldPath)
+	writeEd25519Key(t, newPath)
+
+	old := keys.New(keys.Config{SigningKeyPath: oldPath})
+	oldToken, err := old.Sign(claims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := keys.New(keys.Config{SigningKeyPath: newPath, VerificationKeyPaths: []string{oldPath}})
+	if _, err := jwt.Parse(oldToken, rotated.Keyfunc); err != nil {
+		t.Errorf("a token signed with the previous key should still verify: %v", err)
+	}
+	newToken, _ := rotated.Sign(claims())
+	if _, err := jwt.Parse(newToken, old.Keyfunc); err == nil {
+		t.Error("a token signed with an unknown key verified")
+	}
+}
+
+func Test_Keyring_RejectsOtherAlgorithms(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rsa.key")
+	writeRSAKey(t, path)
+	k := keys.New(keys.Config{SigningKeyPath: path})
+	signing, _ := k.SigningKey()
+
+	// the classic trick of signing with HMAC using the public key as the secret
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims())
+	token.Header["kid"] = signing.ID
+	der, _ := x509.MarshalPKIXPublicKey(signing.Public)
+	forged, _ := token.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if _, err := jwt.Parse(forged, k.Keyfunc); err == nil {
+		t.Error("a token claiming another algorithm than its key verified")
+	}
+}
+
+func Test_Keyring_ReloadsOnChange(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "signing.key")
+	writeRSAKey(t, path)
+
+	k := keys.New(keys.Config{SigningKeyPath: path, CheckInterval: time.Millisecond})
+	before, err := k.SigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeEd25519Key(t, path)
+	later := time.Now().Add(time.Second)
+	os.Chtimes(path, later, later)
+	time.Sleep(2 * time.Millisecond)
+
+	after, err := k.SigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.ID == before.ID || after.Method.Alg() != "EdDSA" {
+		t.Error("the keyring didn't pick up the new key")
+	}
+}
+
+func Test_Keyring_JWKS(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	rsaPath := filepath.Join(dir, "rsa.key")
+	edPath := filepath.Join(dir, "ed.key")
+	writeRSAKey(t, rsaPath)
+	writeEd25519Key(t, edPath)
+
+	k := keys.New(keys.Config{SigningKeyPath: rsaPath, VerificationKeyPaths: []string{"previous=" + edPath}})
+	set, err := k.JWKS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Keys) != 2 {
+		t.Fatalf("expected 2 keys, got %d", len(set.Keys))
+	}
+	if set.Keys[0].KeyType != "RSA" || set.Keys[0].Alg != "RS256" || set.Keys[0].E != "AQAB" {
+		t.Errorf("unexpected RSA key %+v", set.Keys[0])
+	}
+	if set.Keys[1].ID != "previous" || set.Keys[1].KeyType != "OKP" || set.Keys[1].Curve != "Ed25519" {
+		t.Errorf("unexpected Ed25519 key %+v", set.Keys[1])
+	}
+
+	secretPath := filepath.Join(dir, "secret")
+	ioutil.WriteFile(secretPath, []byte("a secret"), 0600)
+	set, _ = keys.New(keys.Config{SigningKeyPath: secretPath}).JWKS()
+	if len(set.Keys) != 0 {
+		t.Error("an HMAC secret was published")
+	}
+}
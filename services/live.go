@@ -0,0 +1,84 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services/hub"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+// Live - everyone watching an encounter as it happens
+var Live = hub.New()
+
+// events logged inside a transaction wait here until it is committed, so nobody is told about something that got rolled back
+var pending = struct {
+	sync.Mutex
+	events map[*pop.Connection][]models.EncounterEvent
+}{events: map[*pop.Connection][]models.EncounterEvent{}}
+
+func queueEvent(tx *pop.Connection, event models.EncounterEvent) {
+	if tx.TX == nil {
+		publishEvents([]models.EncounterEvent{event})
+		return
+	}
+	pending.Lock()
+	defer pending.Unlock()
+	pending.events[tx] = append(pending.events[tx], event)
+}
+
+// PublishEvents - pushes what was logged in a transaction that has been committed, followed by the new state of each encounter it touched
+func PublishEvents(tx *pop.Connection) {
+	pending.Lock()
+	events := pending.events[tx]
+	delete(pending.events, tx)
+	pending.Unlock()
+
+	publishEvents(events)
+}
+
+// DiscardEvents - forgets what was logged in a transaction that has been rolled back
+func DiscardEvents(tx *pop.Connection) {
+	pending.Lock()
+	defer pending.Unlock()
+	delete(pending.events, tx)
+}
+
+func publishEvents(events []models.EncounterEvent) {
+	touched := []UUID.UUID{}
+	seen := map[UUID.UUID]bool{}
+	for _, event := range events {
+		if Live.Subscribers(event.EncounterID) == 0 {
+			continue
+		}
+		m, err := hub.NewMessage(event.Sequence, "event", event)
+		if err != nil {
+			continue
+		}
+		Live.Publish(event.EncounterID, m)
+		if !seen[event.EncounterID] {
+			seen[event.EncounterID] = true
+			touched = append(touched, event.EncounterID)
+		}
+	}
+
+	for _, encounterID := range touched {
+		if m, err := StateMessage(models.DB, encounterID); err == nil {
+			Live.Publish(encounterID, m)
+		}
+	}
+}
+
+// StateMessage - the current state of an encounter, ready to push
+func StateMessage(tx *pop.Connection, encounterID UUID.UUID) (hub.Message, error) {
+	encounter, err := GetEncounter(tx, encounterID.String())
+	if err != nil {
+		return hub.Message{}, err
+	}
+	state, err := GetEncounterState(tx, encounter)
+	if err != nil {
+		return hub.Message{}, err
+	}
+	return hub.NewMessage(0, "state", state)
+}
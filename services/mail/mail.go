@@ -0,0 +1,104 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message - an email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer - something that delivers emails
+type Mailer interface {
+	Send(message Message) error
+}
+
+// SMTPMailer - sends emails through an SMTP server, authenticating if a username is given
+type SMTPMailer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// Send - delivers the message
+func (m *SMTPMailer) Send(message Message) error {
+	var auth smtp.Auth
+	if len(m.Username) > 0 {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+	addr := net.JoinHostPort(m.Host, strconv.Itoa(m.Port))
+	return smtp.SendMail(addr, auth, m.From, []string{message.To}, format(m.From, message))
+}
+
+// format - the message as headers and a plain text body
+func format(from string, message Message) []byte {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("From: %s\r\n", header(from)))
+	b.WriteString(fmt.Sprintf("To: %s\r\n", header(message.To)))
+	b.WriteString(fmt.Sprintf("Subject: %s\r\n", header(message.Subject)))
+	b.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.Replace(message.Body, "\n", "\r\n", -1))
+	return []byte(b.String())
+}
+
+// header - keeps a header value on one line so it can't add headers of its own
+func header(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
+
+// FileMailer - writes emails to a file instead of sending them, or to the log if there's no file.
+// Meant for development and tests
+type FileMailer struct {
+	Path string
+	From string
+
+	mu sync.Mutex
+}
+
+// Send - appends the message to the file or the log
+func (m *FileMailer) Send(message Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.Path) == 0 {
+		log.Printf("mail to %s: %s\n%s", message.To, message.Subject, message.Body)
+		return nil
+	}
+
+	f, err := os.OpenFile(m.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.WriteString(f, string(format(m.From, message))+"\r\n\r\n")
+	return err
+}
+
+// ErrUnavailable - what UnavailableMailer answers every email with
+var ErrUnavailable = errors.New("no way of sending emails has been set up")
+
+// UnavailableMailer - refuses every email, for when there's nowhere safe to send them
+type UnavailableMailer struct{}
+
+// Send - fails without sending anything
+func (m UnavailableMailer) Send(message Message) error {
+	return ErrUnavailable
+}
@@ -0,0 +1,69 @@
+package mail_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dosaki/emote_combat_server/services/mail"
+)
+
+func Test_FileMailer_Send(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mail.txt")
+
+	var m mail.Mailer = &mail.FileMailer{Path: path, From: "server@example.com"}
+	for _, to := range []string{"first@example.com", "second@example.com"} {
+		if err := m.Send(mail.Message{To: to, Subject: "Hello", Body: "line one\nline two"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	written := string(data)
+	for _, want := range []string{"From: server@example.com", "To: first@example.com", "To: second@example.com", "Subject: Hello", "line one\r\nline two"} {
+		if !strings.Contains(written, want) {
+			t.Errorf("expected the file to contain %q", want)
+		}
+	}
+}
+
+func Test_FileMailer_SendToLog(t *testing.T) {
+	m := &mail.FileMailer{}
+	if err := m.Send(mail.Message{To: "someone@example.com", Subject: "Hello", Body: "hi"}); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_FileMailer_HeaderInjection(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mail.txt")
+
+	m := &mail.FileMailer{Path: path}
+	m.Send(mail.Message{To: "someone@example.com\r\nBcc: everyone@example.com", Subject: "Hello", Body: "hi"})
+
+	data, _ := ioutil.ReadFile(path)
+	if strings.Contains(string(data), "\r\nBcc:") {
+		t.Error("a header value added a header")
+	}
+}
+
+func Test_UnavailableMailer_Send(t *testing.T) {
+	var m mail.Mailer = mail.UnavailableMailer{}
+	if err := m.Send(mail.Message{To: "someone@example.com", Subject: "Hello", Body: "secret"}); err != mail.ErrUnavailable {
+		t.Fatalf("expected %v, got %v", mail.ErrUnavailable, err)
+	}
+}
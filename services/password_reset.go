@@ -0,0 +1,140 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services/mail"
+	"github.com/dosaki/emote_combat_server/services/revocation"
+	"github.com/gobuffalo/envy"
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+)
+
+// DefaultPasswordResetLifetime - how long a password reset can be used for unless PASSWORD_RESET_LIFETIME says otherwise
+var DefaultPasswordResetLifetime = time.Hour
+
+// Mailer - sends emails through SMTP_HOST if it is set, otherwise writes them to MAIL_FILE.
+// Emails only go to the log in development and tests; anywhere else, without SMTP_HOST or MAIL_FILE, they aren't sent at all
+var Mailer mail.Mailer = newMailer()
+
+func newMailer() mail.Mailer {
+	from := envy.Get("MAIL_FROM", "noreply@localhost")
+	host := envy.Get("SMTP_HOST", "")
+	if len(host) == 0 {
+		path := envy.Get("MAIL_FILE", "")
+		env := envy.Get("GO_ENV", "development")
+		if len(path) == 0 && env != "development" && env != "test" {
+			return mail.UnavailableMailer{}
+		}
+		return &mail.FileMailer{Path: path, From: from}
+	}
+	port, err := strconv.Atoi(envy.Get("SMTP_PORT", "587"))
+	if err != nil {
+		port = 587
+	}
+	return &mail.SMTPMailer{
+		Host:     host,
+		Port:     port,
+		Username: envy.Get("SMTP_USERNAME", ""),
+		Password: envy.Get("SMTP_PASSWORD", ""),
+		From:     from,
+	}
+}
+
+// PasswordResetLifetime - how long a new password reset can be used for, from PASSWORD_RESET_LIFETIME (e.g. "30m")
+func PasswordResetLifetime() time.Duration {
+	return lifetime("PASSWORD_RESET_LIFETIME", DefaultPasswordResetLifetime)
+}
+
+// RequestPasswordReset - starts a reset for the user with that address and returns the email that gives them its token.
+// Any reset they asked for before stops working. There's no email if there's no such user.
+// The email should only be sent once tx is committed, otherwise the token it carries might never have been saved
+func RequestPasswordReset(tx *pop.Connection, email string) (*mail.Message, error) {
+	var users []models.User
+	if err := tx.Where("email = ?", strings.ToLower(strings.TrimSpace(email))).All(&users); err != nil {
+		return nil, errors.New(messages.UnknownError)
+	}
+	if len(users) == 0 {
+		return nil, nil
+	}
+	user := users[0]
+
+	now := time.Now()
+	err := tx.RawQuery("update password_resets set used_at = ?, updated_at = ? where user_id = ? and used_at is null", now, now, user.ID).Exec()
+	if err != nil {
+		return nil, errors.New(messages.UnknownError)
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	lifetime := PasswordResetLifetime()
+	reset := models.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: revocation.Hash(token),
+		ExpiresAt: now.Add(lifetime),
+	}
+	if tx.Create(&reset) != nil {
+		return nil, errors.New(messages.UnknownError)
+	}
+
+	// PASSWORD_RESET_URL can point at a page that takes the token, e.g. https://example.com/reset?token={token}
+	link := token
+	if url := envy.Get("PASSWORD_RESET_URL", ""); len(url) > 0 {
+		link = strings.Replace(url, "{token}", token, -1)
+	}
+	body := fmt.Sprintf("Hi %s,\n\nSomeone asked to reset the password of your account. "+
+		"If it was you, use this within %s to choose a new one:\n\n%s\n\nIf it wasn't, you can ignore this email.\n",
+		user.Name, lifetime, link)
+	return &mail.Message{To: user.Email, Subject: "Resetting your password", Body: body}, nil
+}
+
+// ResetPassword - gives the user a reset token belongs to a new password, as long as the token hasn't expired or been used.
+// The user is logged out everywhere else: every login's refresh tokens are revoked, which ends its access tokens too
+func ResetPassword(tx *pop.Connection, token string, password string, confirmation string) (*validate.Errors, error) {
+	verrs := validate.NewErrors()
+
+	var resets []models.PasswordReset
+	err := tx.Where("token_hash = ?", revocation.Hash(token)).All(&resets)
+	if err != nil || len(resets) == 0 || !resets[0].Usable(time.Now()) {
+		return verrs, errors.New(messages.InvalidResetTokenError)
+	}
+	reset := resets[0]
+
+	var users []models.User
+	if err := tx.Where("id = ?", reset.UserID).All(&users); err != nil || len(users) == 0 {
+		return verrs, errors.New(messages.InvalidResetTokenError)
+	}
+	user := users[0]
+
+	verrs, err = user.SetPassword(password, confirmation)
+	if err != nil || verrs.HasAny() {
+		return verrs, err
+	}
+
+	// only one of two requests racing with the same token gets to use it
+	now := time.Now()
+	count, err := tx.RawQuery("update password_resets set used_at = ?, updated_at = ? where id = ? and used_at is null", now, now, reset.ID).ExecWithCount()
+	if err != nil {
+		return verrs, errors.New(messages.UnknownError)
+	}
+	if count == 0 {
+		return verrs, errors.New(messages.InvalidResetTokenError)
+	}
+
+	if tx.Save(&user) != nil {
+		return verrs, errors.New(messages.UnknownError)
+	}
+	err = tx.RawQuery("update refresh_tokens set revoked_at = ?, updated_at = ? where user_id = ? and revoked_at is null", now, now, user.ID).Exec()
+	if err != nil {
+		return verrs, errors.New(messages.UnknownError)
+	}
+	return verrs, nil
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+)
+
+// UnmetPrerequisite - a prerequisite a character's sheet doesn't meet yet
+type UnmetPrerequisite struct {
+	models.SkillPrerequisite
+	RequiredSkill string `json:"required_skill"`
+	CurrentValue  int    `json:"current_value"`
+}
+
+// SheetEntryView - a sheet entry along with the prerequisites it doesn't meet, so clients can grey out what can't be raised
+type SheetEntryView struct {
+	models.CharacterSheetEntry
+	UnmetPrerequisites []UnmetPrerequisite `json:"unmet_prerequisites"`
+}
+
+// GetPrerequisites - every prerequisite, by the skill it applies to
+func GetPrerequisites(tx *pop.Connection) (map[UUID.UUID][]models.SkillPrerequisite, error) {
+	bySkill := map[UUID.UUID][]models.SkillPrerequisite{}
+	var prerequisites []models.SkillPrerequisite
+	if err := tx.All(&prerequisites); err != nil {
+		return bySkill, errors.New(messages.ProblemGettingPrerequisitesError)
+	}
+	for _, p := range prerequisites {
+		bySkill[p.SkillID] = append(bySkill[p.SkillID], p)
+	}
+	return bySkill, nil
+}
+
+// GetSkillPrerequisites - what a skill requires
+func GetSkillPrerequisites(tx *pop.Connection, skillID UUID.UUID) ([]models.SkillPrerequisite, error) {
+	prerequisites := []models.SkillPrerequisite{}
+	if err := tx.Where("skill_id = ?", skillID).All(&prerequisites); err != nil {
+		return prerequisites, errors.New(messages.ProblemGettingPrerequisitesError)
+	}
+	return prerequisites, nil
+}
+
+// AddPrerequisite - makes a skill require another one to be at a minimum value
+func AddPrerequisite(tx *pop.Connection, skill models.Skill, body models.SkillPrerequisite) (models.SkillPrerequisite, *validate.Errors, error) {
+	required, err := FindSkill(tx, UUID.Nil, body.RequiredSkillID, "")
+	if err != nil {
+		return models.SkillPrerequisite{}, nil, err
+	}
+	if required.SkillSetID != skill.SkillSetID {
+		return models.SkillPrerequisite{}, nil, errors.New(messages.PrerequisiteInOtherSkillSetError)
+	}
+
+	existing, err := GetSkillPrerequisites(tx, skill.ID)
+	if err != nil {
+		return models.SkillPrerequisite{}, nil, err
+	}
+	for _, p := range existing {
+		if p.RequiredSkillID == body.RequiredSkillID {
+			return models.SkillPrerequisite{}, nil, errors.New(messages.DuplicatePrerequisiteError)
+		}
+	}
+
+	all, err := GetPrerequisites(tx)
+	if err != nil {
+		return models.SkillPrerequisite{}, nil, err
+	}
+	if RequiresSkill(all, body.RequiredSkillID, skill.ID) {
+		return models.SkillPrerequisite{}, nil, errors.New(messages.PrerequisiteCycleError)
+	}
+
+	prerequisite := models.SkillPrerequisite{
+		SkillID:         skill.ID,
+		RequiredSkillID: body.RequiredSkillID,
+		MinimumValue:    body.MinimumValue,
+	}
+	verrs, err := tx.ValidateAndCreate(&prerequisite)
+	if err != nil {
+		return models.SkillPrerequisite{}, verrs, errors.New(messages.UnknownError)
+	}
+	return prerequisite, verrs, nil
+}
+
+// RequiresSkill - whether skillID needs requiredID, directly or through a chain of prerequisites. A skill always needs itself
+func RequiresSkill(prerequisites map[UUID.UUID][]models.SkillPrerequisite, skillID UUID.UUID, requiredID UUID.UUID) bool {
+	seen := map[UUID.UUID]bool{}
+	pending := []UUID.UUID{skillID}
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		if id == requiredID {
+			return true
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		for _, p := range prerequisites[id] {
+			pending = append(pending, p.RequiredSkillID)
+		}
+	}
+	return false
+}
+
+// RemovePrerequisite - drops one of a skill's prerequisites
+func RemovePrerequisite(tx *pop.Connection, skillID UUID.UUID, uuidString string) error {
+	var prerequisites []models.SkillPrerequisite
+	err := tx.Where("skill_id = ?", skillID).Where("id = ?", uuidString).All(&prerequisites)
+	if err != nil || len(prerequisites) == 0 {
+		return errors.New(messages.PrerequisiteNotFoundError)
+	}
+	if tx.Destroy(&prerequisites[0]) != nil {
+		return errors.New(messages.UnknownError)
+	}
+	return nil
+}
+
+// UnmetPrerequisites - which of a skill's prerequisites a sheet doesn't meet; a skill missing from the sheet counts as 0
+func UnmetPrerequisites(skills map[UUID.UUID]models.Skill, prerequisites []models.SkillPrerequisite, values map[UUID.UUID]int) []UnmetPrerequisite {
+	unmet := []UnmetPrerequisite{}
+	for _, p := range prerequisites {
+		if values[p.RequiredSkillID] < p.MinimumValue {
+			unmet = append(unmet, UnmetPrerequisite{
+				SkillPrerequisite: p,
+				RequiredSkill:     skills[p.RequiredSkillID].Name,
+				CurrentValue:      values[p.RequiredSkillID],
+			})
+		}
+	}
+	return unmet
+}
+
+// GetSheetView - a character's sheet with derived values worked out and every entry's unmet prerequisites
+func GetSheetView(tx *pop.Connection, characterID UUID.UUID) ([]SheetEntryView, error) {
+	views := []SheetEntryView{}
+
+	sheet, err := GetSheet(tx, characterID)
+	if err != nil {
+		return views, err
+	}
+	skills, err := GetSkillsByID(tx)
+	if err != nil {
+		return views, err
+	}
+	prerequisites, err := GetPrerequisites(tx)
+	if err != nil {
+		return views, err
+	}
+
+	sheet = DeriveValues(skills, sheet)
+	values := map[UUID.UUID]int{}
+	for _, entry := range sheet {
+		values[entry.SkillID] = entry.Value
+	}
+	for _, entry := range sheet {
+		views = append(views, SheetEntryView{
+			CharacterSheetEntry: entry,
+			UnmetPrerequisites:  UnmetPrerequisites(skills, prerequisites[entry.SkillID], values),
+		})
+	}
+	return views, nil
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/dosaki/emote_combat_server/models"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+func Test_RequiresSkill(t *testing.T) {
+	swords := UUID.Must(UUID.NewV4())
+	parry := UUID.Must(UUID.NewV4())
+	riposte := UUID.Must(UUID.NewV4())
+	archery := UUID.Must(UUID.NewV4())
+	loopA := UUID.Must(UUID.NewV4())
+	loopB := UUID.Must(UUID.NewV4())
+	prerequisites := map[UUID.UUID][]models.SkillPrerequisite{
+		parry:   {{SkillID: parry, RequiredSkillID: swords}},
+		riposte: {{SkillID: riposte, RequiredSkillID: parry}},
+		loopA:   {{SkillID: loopA, RequiredSkillID: loopB}},
+		loopB:   {{SkillID: loopB, RequiredSkillID: loopA}},
+	}
+
+	cases := []struct {
+		name     string
+		skill    UUID.UUID
+		required UUID.UUID
+		requires bool
+	}{
+		{"itself", swords, swords, true},
+		{"directly", parry, swords, true},
+		{"through a chain", riposte, swords, true},
+		{"not the other way round", swords, riposte, false},
+		{"unrelated", riposte, archery, false},
+		{"no prerequisites", archery, swords, false},
+		{"a loop ends", loopA, archery, false},
+	}
+	for _, tc := range cases {
+		if got := RequiresSkill(prerequisites, tc.skill, tc.required); got != tc.requires {
+			t.Errorf("%s: expected %t, got %t", tc.name, tc.requires, got)
+		}
+	}
+}
+
+func Test_UnmetPrerequisites(t *testing.T) {
+	swords := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Swords"}
+	footwork := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Footwork"}
+	skills := map[UUID.UUID]models.Skill{swords.ID: swords, footwork.ID: footwork}
+	prerequisites := []models.SkillPrerequisite{
+		{RequiredSkillID: swords.ID, MinimumValue: 3},
+		{RequiredSkillID: footwork.ID, MinimumValue: 2},
+	}
+
+	cases := []struct {
+		name   string
+		values map[UUID.UUID]int
+		unmet  []string
+	}{
+		{"all met", map[UUID.UUID]int{swords.ID: 5, footwork.ID: 2}, []string{}},
+		{"exactly at the minimum", map[UUID.UUID]int{swords.ID: 3, footwork.ID: 2}, []string{}},
+		{"one short", map[UUID.UUID]int{swords.ID: 2, footwork.ID: 2}, []string{"Swords"}},
+		{"missing from the sheet", map[UUID.UUID]int{swords.ID: 3}, []string{"Footwork"}},
+		{"none met", map[UUID.UUID]int{}, []string{"Swords", "Footwork"}},
+	}
+	for _, tc := range cases {
+		unmet := UnmetPrerequisites(skills, prerequisites, tc.values)
+		if len(unmet) != len(tc.unmet) {
+			t.Errorf("%s: expected %d unmet, got %d", tc.name, len(tc.unmet), len(unmet))
+			continue
+		}
+		for i, name := range tc.unmet {
+			if unmet[i].RequiredSkill != name {
+				t.Errorf("%s: expected %s, got %s", tc.name, name, unmet[i].RequiredSkill)
+			}
+			if unmet[i].CurrentValue != tc.values[unmet[i].RequiredSkillID] {
+				t.Errorf("%s: expected the current value of %s to be %d, got %d", tc.name, name, tc.values[unmet[i].RequiredSkillID], unmet[i].CurrentValue)
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services/revocation"
+	"github.com/gobuffalo/envy"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+// DefaultAccessTokenLifetime - how long access tokens last unless ACCESS_TOKEN_LIFETIME says otherwise
+var DefaultAccessTokenLifetime = 60 * time.Minute
+
+// DefaultRefreshTokenLifetime - how long refresh tokens last unless REFRESH_TOKEN_LIFETIME says otherwise
+var DefaultRefreshTokenLifetime = 30 * 24 * time.Hour
+
+// AccessTokenLifetime - how long a new access token lasts, from ACCESS_TOKEN_LIFETIME (e.g. "90m")
+func AccessTokenLifetime() time.Duration {
+	return lifetime("ACCESS_TOKEN_LIFETIME", DefaultAccessTokenLifetime)
+}
+
+// RefreshTokenLifetime - how long a new refresh token lasts, from REFRESH_TOKEN_LIFETIME (e.g. "720h")
+func RefreshTokenLifetime() time.Duration {
+	return lifetime("REFRESH_TOKEN_LIFETIME", DefaultRefreshTokenLifetime)
+}
+
+func lifetime(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(envy.Get(key, ""))
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// randomToken - a new unguessable token, safe to put in a URL
+func randomToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", errors.New(messages.UnknownError)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// IssueRefreshToken - creates a refresh token for a user, in a new family if the family ID is nil.
+// The token itself is only ever returned here, the database only has its hash
+func IssueRefreshToken(tx *pop.Connection, userID UUID.UUID, familyID UUID.UUID) (string, models.RefreshToken, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", models.RefreshToken{}, err
+	}
+
+	if familyID == UUID.Nil {
+		if familyID, err = UUID.NewV4(); err != nil {
+			return "", models.RefreshToken{}, errors.New(messages.UnknownError)
+		}
+	}
+
+	refreshToken := models.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: revocation.Hash(token),
+		ExpiresAt: time.Now().Add(RefreshTokenLifetime()),
+	}
+	if tx.Create(&refreshToken) != nil {
+		return "", models.RefreshToken{}, errors.New(messages.UnknownError)
+	}
+
+	// expired tokens can't be reused any more, so there's no need to keep them around
+	tx.RawQuery("delete from refresh_tokens where user_id = ? and expires_at <= ?", userID, time.Now()).Exec()
+	return token, refreshToken, nil
+}
+
+// RotateRefreshToken - trades a refresh token in for a new one in the same family.
+// A token can only be traded once: trading it again means it has been stolen, so every token in its family is revoked
+func RotateRefreshToken(tx *pop.Connection, token string) (string, models.RefreshToken, error) {
+	var refreshTokens []models.RefreshToken
+	err := tx.Where("token_hash = ?", revocation.Hash(token)).All(&refreshTokens)
+	if err != nil || len(refreshTokens) == 0 {
+		return "", models.RefreshToken{}, errors.New(messages.InvalidRefreshTokenError)
+	}
+	used := refreshTokens[0]
+	now := time.Now()
+
+	if used.UsedAt.Valid || used.RevokedAt.Valid {
+		// the request fails, rolling back its transaction, so this has to be written outside of it
+		if err := RevokeRefreshFamily(models.DB, used.FamilyID); err != nil {
+			return "", models.RefreshToken{}, err
+		}
+		return "", models.RefreshToken{}, errors.New(messages.RefreshTokenReusedError)
+	}
+	if !used.Usable(now) {
+		return "", models.RefreshToken{}, errors.New(messages.InvalidRefreshTokenError)
+	}
+
+	// only one of two requests racing with the same token gets to use it
+	count, err := tx.RawQuery("update refresh_tokens set used_at = ?, updated_at = ? where id = ? and used_at is null", now, now, used.ID).ExecWithCount()
+	if err != nil {
+		return "", models.RefreshToken{}, errors.New(messages.UnknownError)
+	}
+	if count == 0 {
+		// whoever won the race has committed by now and their new token is revoked with the rest of the family.
+		// This transaction still holds a lock on the used token, so it's left out rather than waited on; it can't be used again anyway
+		if err := revokeRefreshFamilyExcept(models.DB, used.FamilyID, used.ID); err != nil {
+			return "", models.RefreshToken{}, err
+		}
+		return "", models.RefreshToken{}, errors.New(messages.RefreshTokenReusedError)
+	}
+
+	return IssueRefreshToken(tx, used.UserID, used.FamilyID)
+}
+
+// RevokeRefreshFamily - revokes every refresh token that came from the same login
+func RevokeRefreshFamily(tx *pop.Connection, familyID UUID.UUID) error {
+	now := time.Now()
+	err := tx.RawQuery("update refresh_tokens set revoked_at = ?, updated_at = ? where family_id = ? and revoked_at is null", now, now, familyID).Exec()
+	if err != nil {
+		return errors.New(messages.UnknownError)
+	}
+	return nil
+}
+
+// revokeRefreshFamilyExcept - revokes a login's refresh tokens one by one, leaving out one of them
+func revokeRefreshFamilyExcept(tx *pop.Connection, familyID UUID.UUID, exceptID UUID.UUID) error {
+	var refreshTokens []models.RefreshToken
+	if err := tx.Where("family_id = ?", familyID).Where("revoked_at is null").All(&refreshTokens); err != nil {
+		return errors.New(messages.UnknownError)
+	}
+	now := time.Now()
+	for _, refreshToken := range refreshTokens {
+		if refreshToken.ID == exceptID {
+			continue
+		}
+		err := tx.RawQuery("update refresh_tokens set revoked_at = ?, updated_at = ? where id = ? and revoked_at is null", now, now, refreshToken.ID).Exec()
+		if err != nil {
+			return errors.New(messages.UnknownError)
+		}
+	}
+	return nil
+}
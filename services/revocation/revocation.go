@@ -0,0 +1,113 @@
+package revocation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"sync"
+	"time"
+)
+
+// Store - keeps track of tokens that were revoked before they expired.
+// Tokens are only remembered until they would have expired anyway, since by then they're no longer valid.
+// Implementations must be safe to use from several requests at once
+type Store interface {
+	// Revoke - remembers a token as revoked until it expires
+	Revoke(token string, expiresAt time.Time) error
+	// IsRevoked - whether a token has been revoked
+	IsRevoked(token string) (bool, error)
+	// Prune - forgets every token that has expired by now, returning how many were forgotten
+	Prune(now time.Time) (int, error)
+}
+
+// Hash - what a token is stored under, so a leaked store doesn't leak usable tokens
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// MemoryStore - a Store that only lasts as long as the process, for tests and single instance development servers
+type MemoryStore struct {
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+}
+
+// NewMemoryStore - an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{revoked: map[string]time.Time{}}
+}
+
+// Revoke - remembers a token as revoked until it expires
+func (m *MemoryStore) Revoke(token string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.revoked[Hash(token)] = expiresAt
+	return nil
+}
+
+// IsRevoked - whether a token has been revoked
+func (m *MemoryStore) IsRevoked(token string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.revoked[Hash(token)]
+	return ok, nil
+}
+
+// Prune - forgets every token that has expired by now
+func (m *MemoryStore) Prune(now time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pruned := 0
+	for hash, expiresAt := range m.revoked {
+		if !expiresAt.After(now) {
+			delete(m.revoked, hash)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+// PruningStore - wraps a store so that it prunes itself as it is used, at most once per interval
+type PruningStore struct {
+	Store
+	Interval time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// WithPruning - a store that prunes the given one whenever it's been more than the interval since it last did
+func WithPruning(store Store, interval time.Duration) *PruningStore {
+	return &PruningStore{Store: store, Interval: interval}
+}
+
+// Revoke - remembers a token as revoked until it expires
+func (p *PruningStore) Revoke(token string, expiresAt time.Time) error {
+	p.prune()
+	return p.Store.Revoke(token, expiresAt)
+}
+
+// IsRevoked - whether a token has been revoked
+func (p *PruningStore) IsRevoked(token string) (bool, error) {
+	p.prune()
+	return p.Store.IsRevoked(token)
+}
+
+// prune - pruning is only housekeeping, so when it fails the request carries on and it's tried again next interval
+func (p *PruningStore) prune() {
+	if _, err := p.MaybePrune(time.Now()); err != nil {
+		log.Println("could not prune revoked tokens", err)
+	}
+}
+
+// MaybePrune - prunes the store if it hasn't been pruned within the interval
+func (p *PruningStore) MaybePrune(now time.Time) (int, error) {
+	p.mu.Lock()
+	if now.Sub(p.last) < p.Interval {
+		p.mu.Unlock()
+		return 0, nil
+	}
+	p.last = now
+	p.mu.Unlock()
+	return p.Store.Prune(now)
+}
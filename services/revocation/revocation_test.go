@@ -0,0 +1,120 @@
+package revocation_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dosaki/emote_combat_server/services/revocation"
+)
+
+func Test_MemoryStore_Revoke(t *testing.T) {
+	s := revocation.NewMemoryStore()
+	if err := s.Revoke("a.b.c", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	if revoked, _ := s.IsRevoked("a.b.c"); !revoked {
+		t.Error("a revoked token isn't revoked")
+	}
+	if revoked, _ := s.IsRevoked("d.e.f"); revoked {
+		t.Error("a token that was never revoked is revoked")
+	}
+}
+
+func Test_MemoryStore_Prune(t *testing.T) {
+	s := revocation.NewMemoryStore()
+	now := time.Now()
+	s.Revoke("expired", now.Add(-time.Minute))
+	s.Revoke("live", now.Add(time.Minute))
+
+	pruned, err := s.Prune(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 1 {
+		t.Errorf("expected 1 token pruned, got %d", pruned)
+	}
+	if revoked, _ := s.IsRevoked("expired"); revoked {
+		t.Error("an expired token wasn't pruned")
+	}
+	if revoked, _ := s.IsRevoked("live"); !revoked {
+		t.Error("a token that hasn't expired was pruned")
+	}
+}
+
+func Test_MemoryStore_Concurrent(t *testing.T) {
+	s := revocation.NewMemoryStore()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			token := string(rune('a' + i%26))
+			s.Revoke(token, time.Now().Add(time.Duration(i)*time.Millisecond))
+			s.IsRevoked(token)
+			s.Prune(time.Now())
+		}(i)
+	}
+	wg.Wait()
+}
+
+func Test_PruningStore_MaybePrune(t *testing.T) {
+	s := revocation.NewMemoryStore()
+	p := revocation.WithPruning(s, time.Hour)
+	now := time.Now()
+
+	s.Revoke("first", now.Add(-time.Minute))
+	if pruned, _ := p.MaybePrune(now); pruned != 1 {
+		t.Errorf("expected the first call to prune, got %d pruned", pruned)
+	}
+
+	s.Revoke("second", now.Add(-time.Minute))
+	if pruned, _ := p.MaybePrune(now.Add(time.Minute)); pruned != 0 {
+		t.Errorf("expected no pruning within the interval, got %d pruned", pruned)
+	}
+	if pruned, _ := p.MaybePrune(now.Add(2 * time.Hour)); pruned != 1 {
+		t.Errorf("expected pruning once the interval passed, got %d pruned", pruned)
+	}
+}
+
+func Test_PruningStore_IsRevoked(t *testing.T) {
+	s := revocation.NewMemoryStore()
+	p := revocation.WithPruning(s, time.Hour)
+	s.Revoke("expired", time.Now().Add(-time.Minute))
+
+	if revoked, _ := p.IsRevoked("expired"); revoked {
+		t.Error("checking a token should have pruned the expired ones first")
+	}
+}
+
+func Test_Hash(t *testing.T) {
+	if revocation.Hash("a.b.c") == "a.b.c" || len(revocation.Hash("a.b.c")) != 64 {
+		t.Error("tokens should be stored as a sha256 hex digest")
+	}
+}
+
+type failingPruneStore struct {
+	*revocation.MemoryStore
+}
+
+func (f failingPruneStore) Prune(now time.Time) (int, error) {
+	return 0, errors.New("prune failed")
+}
+
+func Test_PruningStore_PruneFailure(t *testing.T) {
+	p := revocation.WithPruning(failingPruneStore{revocation.NewMemoryStore()}, 0)
+
+	if err := p.Revoke("a.b.c", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("expected a failed prune not to stop revoking, got %v", err)
+	}
+	revoked, err := p.IsRevoked("a.b.c")
+	if err != nil || !revoked {
+		t.Errorf("expected the token to be revoked despite the failed prune, got %v (%v)", revoked, err)
+	}
+	revoked, err = p.IsRevoked("d.e.f")
+	if err != nil || revoked {
+		t.Errorf("expected other tokens to stay valid despite the failed prune, got %v (%v)", revoked, err)
+	}
+}
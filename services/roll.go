@@ -0,0 +1,101 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services/dice"
+	"github.com/dosaki/emote_combat_server/services/ruleset"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+// DefaultRollExpression - what gets rolled when no expression is given
+const DefaultRollExpression = "1d20"
+
+// FindSkill - returns a skill by its ID or, failing that, by its name in the given skill set.
+// Names are only unique within a set, so the set is needed for them; IDs are found in any set
+func FindSkill(tx *pop.Connection, skillSetID UUID.UUID, skillID UUID.UUID, name string) (models.Skill, error) {
+	skills := []models.Skill{}
+	var err error
+	if skillID != UUID.Nil {
+		err = tx.Where("id = ?", skillID).All(&skills)
+	} else if len(name) > 0 {
+		err = tx.Where("skill_set_id = ?", skillSetID).Where("name = ?", name).All(&skills)
+	} else {
+		return models.Skill{}, errors.New(messages.NoSkillError)
+	}
+	if err != nil || len(skills) == 0 {
+		return models.Skill{}, errors.New(messages.SkillNotFoundError)
+	}
+	return skills[0], nil
+}
+
+// GetSheetEntry - returns a character's sheet entry for a skill
+func GetSheetEntry(tx *pop.Connection, characterID UUID.UUID, skillID UUID.UUID) (models.CharacterSheetEntry, error) {
+	var sheetEntries []models.CharacterSheetEntry
+	err := tx.Where("character_id = ?", characterID).Where("skill_id = ?", skillID).All(&sheetEntries)
+	if err != nil {
+		return models.CharacterSheetEntry{}, errors.New(messages.ProblemGettingSheetEntryError)
+	}
+	if len(sheetEntries) == 0 {
+		return models.CharacterSheetEntry{}, errors.New(messages.SheetNotFoundError)
+	}
+	return sheetEntries[0], nil
+}
+
+// RollSkill - rolls an expression for a character, adding their value in the skill, and stores the result
+func RollSkill(tx *pop.Connection, characterID UUID.UUID, skill models.Skill, expression string) (models.Roll, error) {
+	sheetEntry, err := GetSheetEntry(tx, characterID, skill.ID)
+	if err != nil {
+		return models.Roll{}, err
+	}
+	return RollWithModifier(tx, characterID, skill.ID, sheetEntry.Value, sheetEntry.Value, expression)
+}
+
+// GetRuleset - the ruleset an encounter resolves its rolls with
+func GetRuleset(encounter models.Encounter) (ruleset.Ruleset, error) {
+	rs, err := ruleset.Get(encounter.Ruleset)
+	if err != nil {
+		return nil, errors.New(messages.UnknownRulesetError)
+	}
+	return rs, nil
+}
+
+// RollCheck - rolls a check the way the ruleset says to, for a character with the given value in a skill
+func RollCheck(tx *pop.Connection, rs ruleset.Ruleset, characterID UUID.UUID, skillID UUID.UUID, skillValue int) (models.Roll, ruleset.Outcome, error) {
+	roll, err := RollWithModifier(tx, characterID, skillID, skillValue, rs.Modifier(skillValue), rs.Expression())
+	if err != nil {
+		return roll, ruleset.Outcome{}, err
+	}
+	return roll, rs.Check(roll.Total, skillValue), nil
+}
+
+// RollWithModifier - rolls an expression for a character, adding a flat modifier, and stores the result along with the skill value it was made with
+func RollWithModifier(tx *pop.Connection, characterID UUID.UUID, skillID UUID.UUID, skillValue int, modifier int, expression string) (models.Roll, error) {
+	if len(expression) == 0 {
+		expression = DefaultRollExpression
+	}
+
+	result, err := dice.Roll(expression)
+	if err != nil {
+		return models.Roll{}, err
+	}
+	result.Modify(modifier)
+
+	roll := models.Roll{
+		CharacterID: characterID,
+		SkillID:     skillID,
+		SkillValue:  skillValue,
+		Expression:  result.Expression,
+		Modifier:    result.Modifier,
+		Total:       result.Total,
+		Detail:      result.String(),
+		Breakdown:   result,
+	}
+	if tx.Create(&roll) != nil {
+		return models.Roll{}, errors.New(messages.UnknownError)
+	}
+	return roll, nil
+}
@@ -0,0 +1,44 @@
+package ruleset
+
+// D100 - roll-under: 1d100 must come in at or under the skill value, and the further under the better.
+// An attack that fails its own check never hits; damage goes up a tier for every 20 points of margin.
+type D100 struct{}
+
+// Name - see Ruleset
+func (D100) Name() string {
+	return "d100"
+}
+
+// Expression - see Ruleset
+func (D100) Expression() string {
+	return "1d100"
+}
+
+// Modifier - see Ruleset
+func (D100) Modifier(skillValue int) int {
+	return 0
+}
+
+// Check - see Ruleset
+func (D100) Check(total int, skillValue int) Outcome {
+	return Outcome{Score: skillValue - total, Success: total <= skillValue}
+}
+
+// Margin - see Ruleset
+func (D100) Margin(attack Outcome, defense Outcome) int {
+	if !attack.Success {
+		return 0
+	}
+	if !defense.Success {
+		return attack.Score + 1
+	}
+	return attack.Score - defense.Score
+}
+
+// Damage - see Ruleset
+func (D100) Damage(margin int) int {
+	if margin <= 0 {
+		return 0
+	}
+	return 1 + margin/20
+}
@@ -0,0 +1,40 @@
+package ruleset
+
+// D20 - roll-over: 1d20 plus the skill value, meeting the threshold succeeds and the higher total wins.
+// Every 5 points of margin adds a tier of damage.
+type D20 struct {
+	Threshold int
+}
+
+// Name - see Ruleset
+func (D20) Name() string {
+	return "d20"
+}
+
+// Expression - see Ruleset
+func (D20) Expression() string {
+	return "1d20"
+}
+
+// Modifier - see Ruleset
+func (D20) Modifier(skillValue int) int {
+	return skillValue
+}
+
+// Check - see Ruleset
+func (d D20) Check(total int, skillValue int) Outcome {
+	return Outcome{Score: total, Success: total >= d.Threshold}
+}
+
+// Margin - see Ruleset
+func (D20) Margin(attack Outcome, defense Outcome) int {
+	return attack.Score - defense.Score
+}
+
+// Damage - see Ruleset
+func (D20) Damage(margin int) int {
+	if margin <= 0 {
+		return 0
+	}
+	return 1 + margin/5
+}
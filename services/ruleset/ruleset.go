@@ -0,0 +1,73 @@
+package ruleset
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// Default - the ruleset encounters use when none is picked
+const Default = "d20"
+
+// Outcome - how a single check went. Score is always "higher is better" whatever the dice do.
+type Outcome struct {
+	Score   int  `json:"score"`
+	Success bool `json:"success"`
+}
+
+// Ruleset - the house rules used to resolve rolls in an encounter
+type Ruleset interface {
+	// Name - what encounters refer to the ruleset by
+	Name() string
+	// Expression - the dice rolled for a check
+	Expression() string
+	// Modifier - how much is added to the dice for a given skill value
+	Modifier(skillValue int) int
+	// Check - judges a rolled total (modifier included) made with a given skill value
+	Check(total int, skillValue int) Outcome
+	// Margin - how much an attack beat a defense by; anything above 0 is a hit
+	Margin(attack Outcome, defense Outcome) int
+	// Damage - how much a hit with the given margin hurts
+	Damage(margin int) int
+}
+
+var mu sync.RWMutex
+var registry = map[string]Ruleset{}
+
+// Register - makes a ruleset available to encounters by its name
+func Register(r Ruleset) {
+	mu.Lock()
+	defer mu.Unlock()
+	registry[r.Name()] = r
+}
+
+// Get - returns a ruleset by name; an empty name gives the default one
+func Get(name string) (Ruleset, error) {
+	if len(name) == 0 {
+		name = Default
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	r, ok := registry[name]
+	if !ok {
+		return nil, errors.New("unknown ruleset")
+	}
+	return r, nil
+}
+
+// Names - every registered ruleset, sorted
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := []string{}
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	Register(D20{Threshold: 10})
+	Register(D100{})
+}
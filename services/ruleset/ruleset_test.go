@@ -0,0 +1,65 @@
+package ruleset_test
+
+import (
+	"testing"
+
+	"github.com/dosaki/emote_combat_server/services/ruleset"
+)
+
+func Test_Get(t *testing.T) {
+	for _, name := range []string{"d20", "d100"} {
+		r, err := ruleset.Get(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if r.Name() != name {
+			t.Errorf("expected %s, got %s", name, r.Name())
+		}
+	}
+
+	r, err := ruleset.Get("")
+	if err != nil || r.Name() != ruleset.Default {
+		t.Errorf("expected the default ruleset, got %v (%v)", r, err)
+	}
+
+	if _, err := ruleset.Get("d6"); err == nil {
+		t.Error("expected an error for an unknown ruleset")
+	}
+}
+
+func Test_D20(t *testing.T) {
+	r, _ := ruleset.Get("d20")
+	attack := r.Check(r.Modifier(5)+12, 5)
+	defense := r.Check(r.Modifier(3)+4, 3)
+	if !attack.Success || defense.Success {
+		t.Errorf("expected 17 to succeed and 7 to fail, got %v and %v", attack, defense)
+	}
+	margin := r.Margin(attack, defense)
+	if margin != 10 {
+		t.Errorf("expected a margin of 10, got %d", margin)
+	}
+	if r.Damage(margin) != 3 || r.Damage(0) != 0 {
+		t.Errorf("unexpected damage %d / %d", r.Damage(margin), r.Damage(0))
+	}
+}
+
+func Test_D100(t *testing.T) {
+	r, _ := ruleset.Get("d100")
+	if r.Modifier(60) != 0 {
+		t.Error("roll-under should not add the skill to the dice")
+	}
+
+	attack := r.Check(20, 60)
+	defense := r.Check(45, 50)
+	if !attack.Success || !defense.Success {
+		t.Errorf("expected both checks to succeed, got %v and %v", attack, defense)
+	}
+	if margin := r.Margin(attack, defense); margin != 35 || r.Damage(margin) != 2 {
+		t.Errorf("unexpected margin %d", margin)
+	}
+
+	failed := r.Check(70, 60)
+	if failed.Success || r.Margin(failed, r.Check(99, 10)) != 0 {
+		t.Error("a failed attack should never hit")
+	}
+}
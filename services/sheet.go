@@ -0,0 +1,367 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/envy"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+)
+
+// DefaultPointBudget - the budget new characters get, from POINT_BUDGET. Without one they can spend freely
+func DefaultPointBudget() nulls.Int {
+	budget, err := strconv.Atoi(envy.Get("POINT_BUDGET", ""))
+	if err != nil {
+		return nulls.Int{}
+	}
+	return nulls.NewInt(budget)
+}
+
+// EntryCost - the points spent raising a skill from its starting value; going below it gives points back
+func EntryCost(skill models.Skill, value int) int {
+	return (value - skill.StartingValue) * skill.Cost
+}
+
+// SpentPoints - the points spent on a whole sheet; skills derived from their subskills aren't bought
+func SpentPoints(skills map[UUID.UUID]models.Skill, sheet []models.CharacterSheetEntry) int {
+	spent := 0
+	for _, entry := range sheet {
+		if skill, ok := skills[entry.SkillID]; ok && !skill.IsDerived() {
+			spent += EntryCost(skill, entry.Value)
+		}
+	}
+	return spent
+}
+
+// GetSkillsByID - every skill, by ID
+func GetSkillsByID(tx *pop.Connection) (map[UUID.UUID]models.Skill, error) {
+	byID := map[UUID.UUID]models.Skill{}
+	var skills []models.Skill
+	if err := tx.All(&skills); err != nil {
+		return byID, errors.New(messages.ProblemGettingSkillsError)
+	}
+	for _, skill := range skills {
+		byID[skill.ID] = skill
+	}
+	return byID, nil
+}
+
+// GetSheet - every entry of a character's sheet
+func GetSheet(tx *pop.Connection, characterID UUID.UUID) ([]models.CharacterSheetEntry, error) {
+	sheet := []models.CharacterSheetEntry{}
+	if err := tx.Where("character_id = ?", characterID).All(&sheet); err != nil {
+		return sheet, errors.New(messages.ProblemGettingSheetEntryError)
+	}
+	return sheet, nil
+}
+
+// ProposedSheet - a character's sheet as it would be with some entries changed or added
+func ProposedSheet(current []models.CharacterSheetEntry, changes []models.CharacterSheetEntry) []models.CharacterSheetEntry {
+	proposed := make([]models.CharacterSheetEntry, len(current))
+	copy(proposed, current)
+	for _, change := range changes {
+		found := false
+		for i := range proposed {
+			if change.ID != UUID.Nil && proposed[i].ID == change.ID {
+				proposed[i] = change
+				found = true
+			}
+		}
+		if !found {
+			proposed = append(proposed, change)
+		}
+	}
+	return proposed
+}
+
+// CheckSheet - validates a character's sheet with the given changes applied, before any of them is saved
+func CheckSheet(tx *pop.Connection, character models.Character, changes []models.CharacterSheetEntry) (*validate.Errors, error) {
+	verrs, _, err := checkSheet(tx, character, changes)
+	return verrs, err
+}
+
+// checkSheet - validates the sheet and returns it as it would be saved, derived values included
+func checkSheet(tx *pop.Connection, character models.Character, changes []models.CharacterSheetEntry) (*validate.Errors, []models.CharacterSheetEntry, error) {
+	verrs := validate.NewErrors()
+
+	current, err := GetSheet(tx, character.ID)
+	if err != nil {
+		return verrs, nil, err
+	}
+	skills, err := GetSkillsByID(tx)
+	if err != nil {
+		return verrs, nil, err
+	}
+	prerequisites, err := GetPrerequisites(tx)
+	if err != nil {
+		return verrs, nil, err
+	}
+
+	for _, change := range changes {
+		skill, ok := skills[change.SkillID]
+		if !ok {
+			verrs.Add("skill_id", fmt.Sprintf(messages.SheetEntrySkillNotFoundError, change.SkillID))
+		} else if skill.SkillSetID != character.SkillSetID {
+			verrs.Add("skill_id", fmt.Sprintf(messages.SkillNotInSkillSetError, skill.Name))
+		}
+	}
+	if verrs.HasAny() {
+		return verrs, nil, nil
+	}
+
+	checkBounds(verrs, skills, changes)
+	proposed := DeriveValues(skills, ProposedSheet(current, changes))
+	checkChildRules(verrs, skills, proposed, changes)
+	checkPrerequisites(verrs, skills, prerequisites, current, proposed, changes)
+	checkBudget(verrs, character, skills, current, proposed, changes)
+	return verrs, proposed, nil
+}
+
+// DeriveValues - works out the value of every skill that takes it from its subskills, from the bottom of the tree up.
+// A derived skill with no subskills on the sheet keeps the value it has
+func DeriveValues(skills map[UUID.UUID]models.Skill, sheet []models.CharacterSheetEntry) []models.CharacterSheetEntry {
+	values := map[UUID.UUID]int{}
+	for _, entry := range sheet {
+		values[entry.SkillID] = entry.Value
+	}
+	children := map[UUID.UUID][]UUID.UUID{}
+	for _, skill := range skills {
+		if skill.ParentSkillID != UUID.Nil {
+			children[skill.ParentSkillID] = append(children[skill.ParentSkillID], skill.ID)
+		}
+	}
+
+	derived := map[UUID.UUID]int{}
+	visiting := map[UUID.UUID]bool{}
+	var derive func(skillID UUID.UUID) (int, bool)
+	derive = func(skillID UUID.UUID) (int, bool) {
+		if value, ok := derived[skillID]; ok {
+			return value, true
+		}
+		value, onSheet := values[skillID]
+		skill := skills[skillID]
+		// a broken hierarchy that loops back on itself can't be derived
+		if !skill.IsDerived() || visiting[skillID] {
+			return value, onSheet
+		}
+		visiting[skillID] = true
+		defer delete(visiting, skillID)
+
+		total, count := 0, 0
+		for _, childID := range children[skillID] {
+			if childValue, ok := derive(childID); ok {
+				total += childValue
+				count++
+			}
+		}
+		if count > 0 {
+			value = skill.Derive(total, count)
+		}
+		derived[skillID] = value
+		return value, onSheet
+	}
+
+	result := make([]models.CharacterSheetEntry, len(sheet))
+	for i, entry := range sheet {
+		result[i] = entry
+		result[i].Value, _ = derive(entry.SkillID)
+	}
+	return result
+}
+
+// checkChildRules - a subskill of a capped skill can't go above it. Only breaches involving a changed entry are reported
+func checkChildRules(verrs *validate.Errors, skills map[UUID.UUID]models.Skill, proposed []models.CharacterSheetEntry, changes []models.CharacterSheetEntry) {
+	changed := map[UUID.UUID]bool{}
+	for _, change := range changes {
+		changed[change.SkillID] = true
+	}
+	values := map[UUID.UUID]int{}
+	for _, entry := range proposed {
+		values[entry.SkillID] = entry.Value
+	}
+
+	for _, entry := range proposed {
+		parent, ok := skills[skills[entry.SkillID].ParentSkillID]
+		if !ok || parent.ChildRule != models.ChildRuleCapped {
+			continue
+		}
+		parentValue, onSheet := values[parent.ID]
+		if !onSheet || entry.Value <= parentValue || !(changed[entry.SkillID] || changed[parent.ID]) {
+			continue
+		}
+		verrs.Add("entries", fmt.Sprintf(messages.AboveParentError, skills[entry.SkillID].Name, entry.Value, parent.Name, parentValue))
+	}
+}
+
+// CheckChildRuleChange - the sheets that already break a skill's new child rule. Sheets aren't changed to fit the rule,
+// so it can't be applied until they're fixed. Skills that become sum or average need nothing: derived values are worked
+// out again whenever a sheet is read or saved
+func CheckChildRuleChange(tx *pop.Connection, skill models.Skill) (*validate.Errors, error) {
+	verrs := validate.NewErrors()
+	if skill.ChildRule != models.ChildRuleCapped {
+		return verrs, nil
+	}
+
+	skills, err := GetSkillsByID(tx)
+	if err != nil {
+		return verrs, err
+	}
+	skills[skill.ID] = skill
+	var parents []models.CharacterSheetEntry
+	if err := tx.Where("skill_id = ?", skill.ID).All(&parents); err != nil {
+		return verrs, errors.New(messages.ProblemGettingSheetEntryError)
+	}
+	for _, parent := range parents {
+		sheet, err := GetSheet(tx, parent.CharacterID)
+		if err != nil {
+			return verrs, err
+		}
+		sheetErrs := validate.NewErrors()
+		checkChildRules(sheetErrs, skills, DeriveValues(skills, sheet), []models.CharacterSheetEntry{parent})
+		for _, message := range sheetErrs.Get("entries") {
+			verrs.Add("entries", fmt.Sprintf(messages.SheetBreaksChildRuleError, parent.CharacterID, message))
+		}
+	}
+	return verrs, nil
+}
+
+// checkBounds - every changed entry has to be within its skill's minimum and maximum. This is what the entries' own
+// validation checks too, but against skills that are already loaded, so a whole sheet can be checked at once
+func checkBounds(verrs *validate.Errors, skills map[UUID.UUID]models.Skill, changes []models.CharacterSheetEntry) {
+	for _, change := range changes {
+		skill := skills[change.SkillID]
+		if !skill.IsDerived() && !skill.InBounds(change.Value) {
+			verrs.Add("entries", fmt.Sprintf(messages.OutOfBoundsError, skill.Name, change.Value, skill.Bounds()))
+		}
+	}
+}
+
+// checkPrerequisites - an entry can only be bought (created above its starting value) or raised once its skill's prerequisites are met
+func checkPrerequisites(verrs *validate.Errors, skills map[UUID.UUID]models.Skill, prerequisites map[UUID.UUID][]models.SkillPrerequisite, current []models.CharacterSheetEntry, proposed []models.CharacterSheetEntry, changes []models.CharacterSheetEntry) {
+	values := map[UUID.UUID]int{}
+	for _, entry := range proposed {
+		values[entry.SkillID] = entry.Value
+	}
+
+	for _, change := range changes {
+		skill := skills[change.SkillID]
+		previous := skill.StartingValue
+		for _, entry := range current {
+			if change.ID != UUID.Nil && entry.ID == change.ID {
+				previous = entry.Value
+			}
+		}
+		if change.Value <= previous || change.Value <= skill.StartingValue {
+			continue
+		}
+		for _, unmet := range UnmetPrerequisites(skills, prerequisites[change.SkillID], values) {
+			verrs.Add("entries", fmt.Sprintf(messages.UnmetPrerequisiteError, skill.Name, unmet.RequiredSkill, unmet.MinimumValue, unmet.CurrentValue))
+		}
+	}
+}
+
+// checkBudget - a sheet may not spend more than the character's budget; changes that don't raise the spending
+// are let through so a sheet that went over budget (e.g. after a skill's cost changed) can still be fixed
+func checkBudget(verrs *validate.Errors, character models.Character, skills map[UUID.UUID]models.Skill, current []models.CharacterSheetEntry, proposed []models.CharacterSheetEntry, changes []models.CharacterSheetEntry) {
+	if !character.PointBudget.Valid {
+		return
+	}
+	budget := character.PointBudget.Int
+	spent := SpentPoints(skills, proposed)
+	if spent <= budget || spent <= SpentPoints(skills, current) {
+		return
+	}
+
+	verrs.Add("budget", fmt.Sprintf(messages.OverBudgetError, spent, budget))
+	for _, change := range changes {
+		previous := 0
+		for _, entry := range current {
+			if entry.ID == change.ID && change.ID != UUID.Nil {
+				previous = EntryCost(skills[entry.SkillID], entry.Value)
+			}
+		}
+		skill := skills[change.SkillID]
+		if cost := EntryCost(skill, change.Value); cost > previous {
+			verrs.Add("entries", fmt.Sprintf(messages.EntryCostError, skill.Name, change.Value, cost, skill.StartingValue, skill.Cost))
+		}
+	}
+}
+
+// SaveSheetEntries - checks a character's sheet with the given entries changed or added, then saves them all or none.
+// Skills derived from their subskills are saved with their new value too, and every change goes in the sheet's history
+func SaveSheetEntries(tx *pop.Connection, character models.Character, entries []models.CharacterSheetEntry, changedBy UUID.UUID) (*validate.Errors, error) {
+	verrs, proposed, err := checkSheet(tx, character, entries)
+	if err != nil || verrs.HasAny() {
+		return verrs, err
+	}
+
+	derived := map[UUID.UUID]models.CharacterSheetEntry{}
+	for _, entry := range proposed {
+		derived[entry.SkillID] = entry
+	}
+	saving := map[UUID.UUID]bool{}
+	for i := range entries {
+		entries[i].Value = derived[entries[i].SkillID].Value
+		saving[entries[i].SkillID] = true
+	}
+
+	current, err := GetSheet(tx, character.ID)
+	if err != nil {
+		return verrs, err
+	}
+	previous := map[UUID.UUID]models.CharacterSheetEntry{}
+	for _, entry := range current {
+		previous[entry.ID] = entry
+	}
+	for i := range entries {
+		if tx.Save(&entries[i]) != nil {
+			return verrs, errors.New(messages.UnknownError)
+		}
+		if err := recordSavedEntry(tx, changedBy, previous, entries[i]); err != nil {
+			return verrs, err
+		}
+	}
+	for _, entry := range current {
+		if !saving[entry.SkillID] && derived[entry.SkillID].Value != entry.Value {
+			old := entry.Value
+			entry.Value = derived[entry.SkillID].Value
+			if tx.Save(&entry) != nil {
+				return verrs, errors.New(messages.UnknownError)
+			}
+			if err := RecordSheetChange(tx, changedBy, entry, nulls.NewInt(old), nulls.NewInt(entry.Value)); err != nil {
+				return verrs, err
+			}
+		}
+	}
+	return verrs, nil
+}
+
+// recordSavedEntry - records a saved entry in the history against what it was before.
+// An entry moved to another skill counts as the old skill's entry removed and the new one's added
+func recordSavedEntry(tx *pop.Connection, changedBy UUID.UUID, previous map[UUID.UUID]models.CharacterSheetEntry, entry models.CharacterSheetEntry) error {
+	before, ok := previous[entry.ID]
+	if !ok {
+		return RecordSheetChange(tx, changedBy, entry, nulls.Int{}, nulls.NewInt(entry.Value))
+	}
+	if before.SkillID != entry.SkillID {
+		if err := RecordSheetChange(tx, changedBy, before, nulls.NewInt(before.Value), nulls.Int{}); err != nil {
+			return err
+		}
+		return RecordSheetChange(tx, changedBy, entry, nulls.Int{}, nulls.NewInt(entry.Value))
+	}
+	return RecordSheetChange(tx, changedBy, entry, nulls.NewInt(before.Value), nulls.NewInt(entry.Value))
+}
+
+// DeleteSheetEntry - removes an entry from a character's sheet, keeping a note of it in the history
+func DeleteSheetEntry(tx *pop.Connection, entry models.CharacterSheetEntry, changedBy UUID.UUID) error {
+	if tx.Destroy(&entry) != nil {
+		return errors.New(messages.UnknownError)
+	}
+	return RecordSheetChange(tx, changedBy, entry, nulls.NewInt(entry.Value), nulls.Int{})
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+// RecordSheetChange - adds a change of one sheet entry to its character's history, unless nothing actually changed.
+// Pass a null old value when the entry is being added and a null new value when it is being removed.
+func RecordSheetChange(tx *pop.Connection, changedBy UUID.UUID, entry models.CharacterSheetEntry, oldValue nulls.Int, newValue nulls.Int) error {
+	if oldValue == newValue {
+		return nil
+	}
+
+	var last []models.SheetEntryChange
+	if err := tx.Where("character_id = ?", entry.CharacterID).Order("sequence desc").Limit(1).All(&last); err != nil {
+		return errors.New(messages.ProblemGettingSheetHistoryError)
+	}
+	sequence := 1
+	if len(last) > 0 {
+		sequence = last[0].Sequence + 1
+	}
+
+	change := models.SheetEntryChange{
+		CharacterID:  entry.CharacterID,
+		Sequence:     sequence,
+		SheetEntryID: entry.ID,
+		SkillID:      entry.SkillID,
+		OldValue:     oldValue,
+		NewValue:     newValue,
+		ChangedBy:    changedBy,
+	}
+	if tx.Create(&change) != nil {
+		return errors.New(messages.UnknownError)
+	}
+	return nil
+}
+
+// GetSheetHistory - every recorded change to a character's sheet, newest first
+func GetSheetHistory(tx *pop.Connection, characterID UUID.UUID) ([]models.SheetEntryChange, error) {
+	changes := []models.SheetEntryChange{}
+	err := tx.Where("character_id = ?", characterID).Order("sequence desc").All(&changes)
+	if err != nil {
+		return changes, errors.New(messages.ProblemGettingSheetHistoryError)
+	}
+	return changes, nil
+}
+
+// GetSheetAt - a character's sheet as it was at a given time, worked out by undoing every change made since.
+// Timestamps only go down to the second, so the cutoff is the last change made at or before that time and everything
+// numbered after it is undone
+func GetSheetAt(tx *pop.Connection, characterID UUID.UUID, at time.Time) ([]models.CharacterSheetEntry, error) {
+	current, err := GetSheet(tx, characterID)
+	if err != nil {
+		return current, err
+	}
+
+	var cutoff []models.SheetEntryChange
+	err = tx.Where("character_id = ?", characterID).Where("created_at <= ?", at).Order("sequence desc").Limit(1).All(&cutoff)
+	if err != nil {
+		return current, errors.New(messages.ProblemGettingSheetHistoryError)
+	}
+	after := 0
+	if len(cutoff) > 0 {
+		after = cutoff[0].Sequence
+	}
+
+	changes := []models.SheetEntryChange{}
+	err = tx.Where("character_id = ?", characterID).Where("sequence > ?", after).Order("sequence desc").All(&changes)
+	if err != nil {
+		return current, errors.New(messages.ProblemGettingSheetHistoryError)
+	}
+
+	return UndoSheetChanges(current, changes), nil
+}
+
+// UndoSheetChanges - takes a sheet back to before the given changes, which have to be newest first.
+// Entries are matched by their own ID, so a character with two entries for the same skill gets both back right
+func UndoSheetChanges(current []models.CharacterSheetEntry, changes []models.SheetEntryChange) []models.CharacterSheetEntry {
+	sheet := map[UUID.UUID]models.CharacterSheetEntry{}
+	order := []UUID.UUID{}
+	for _, entry := range current {
+		sheet[entry.ID] = entry
+		order = append(order, entry.ID)
+	}
+	for _, change := range changes {
+		if !change.OldValue.Valid {
+			delete(sheet, change.SheetEntryID)
+			continue
+		}
+		entry, ok := sheet[change.SheetEntryID]
+		if !ok {
+			// the entry has been removed since, bring it back
+			entry = models.CharacterSheetEntry{
+				ID:          change.SheetEntryID,
+				CharacterID: change.CharacterID,
+			}
+			order = append(order, change.SheetEntryID)
+		}
+		// an entry moved to another skill goes back to the one it had
+		entry.SkillID = change.SkillID
+		entry.Value = change.OldValue.Int
+		sheet[change.SheetEntryID] = entry
+	}
+
+	past := []models.CharacterSheetEntry{}
+	seen := map[UUID.UUID]bool{}
+	for _, entryID := range order {
+		if entry, ok := sheet[entryID]; ok && !seen[entryID] {
+			past = append(past, entry)
+			seen[entryID] = true
+		}
+	}
+	return past
+}
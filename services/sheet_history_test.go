@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/nulls"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+func Test_UndoSheetChanges(t *testing.T) {
+	swords := UUID.Must(UUID.NewV4())
+	archery := UUID.Must(UUID.NewV4())
+	first := models.CharacterSheetEntry{ID: UUID.Must(UUID.NewV4()), SkillID: swords, Value: 5}
+	second := models.CharacterSheetEntry{ID: UUID.Must(UUID.NewV4()), SkillID: swords, Value: 2}
+	removed := UUID.Must(UUID.NewV4())
+	change := func(entryID UUID.UUID, skillID UUID.UUID, oldValue nulls.Int, newValue nulls.Int) models.SheetEntryChange {
+		return models.SheetEntryChange{SheetEntryID: entryID, SkillID: skillID, OldValue: oldValue, NewValue: newValue}
+	}
+
+	cases := []struct {
+		name    string
+		current []models.CharacterSheetEntry
+		changes []models.SheetEntryChange
+		past    map[UUID.UUID]models.CharacterSheetEntry
+	}{
+		{
+			"nothing to undo",
+			[]models.CharacterSheetEntry{first, second},
+			[]models.SheetEntryChange{},
+			map[UUID.UUID]models.CharacterSheetEntry{first.ID: first, second.ID: second},
+		},
+		{
+			"two entries for the same skill keep their own values",
+			[]models.CharacterSheetEntry{first, second},
+			[]models.SheetEntryChange{change(second.ID, swords, nulls.NewInt(1), nulls.NewInt(2)), change(first.ID, swords, nulls.NewInt(3), nulls.NewInt(5))},
+			map[UUID.UUID]models.CharacterSheetEntry{
+				first.ID:  {ID: first.ID, SkillID: swords, Value: 3},
+				second.ID: {ID: second.ID, SkillID: swords, Value: 1},
+			},
+		},
+		{
+			"added entries go away",
+			[]models.CharacterSheetEntry{first, second},
+			[]models.SheetEntryChange{change(second.ID, swords, nulls.Int{}, nulls.NewInt(2))},
+			map[UUID.UUID]models.CharacterSheetEntry{first.ID: first},
+		},
+		{
+			"removed entries come back",
+			[]models.CharacterSheetEntry{first},
+			[]models.SheetEntryChange{change(removed, archery, nulls.NewInt(4), nulls.Int{})},
+			map[UUID.UUID]models.CharacterSheetEntry{first.ID: first, removed: {ID: removed, SkillID: archery, Value: 4}},
+		},
+		{
+			"moved entries go back to their skill",
+			[]models.CharacterSheetEntry{{ID: first.ID, SkillID: archery, Value: 6}},
+			[]models.SheetEntryChange{change(first.ID, archery, nulls.Int{}, nulls.NewInt(6)), change(first.ID, swords, nulls.NewInt(5), nulls.Int{})},
+			map[UUID.UUID]models.CharacterSheetEntry{first.ID: first},
+		},
+	}
+	for _, tc := range cases {
+		past := UndoSheetChanges(tc.current, tc.changes)
+		if len(past) != len(tc.past) {
+			t.Errorf("%s: expected %d entries, got %d", tc.name, len(tc.past), len(past))
+		}
+		for _, entry := range past {
+			want, ok := tc.past[entry.ID]
+			if !ok || entry.SkillID != want.SkillID || entry.Value != want.Value {
+				t.Errorf("%s: unexpected entry %v", tc.name, entry)
+			}
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+)
+
+// SheetDifference - how one skill differs between a snapshot and the current sheet.
+// A null value means the skill has no entry on that side
+type SheetDifference struct {
+	SkillID       UUID.UUID `json:"skill_id"`
+	SnapshotValue nulls.Int `json:"snapshot_value"`
+	CurrentValue  nulls.Int `json:"current_value"`
+	SnapshotNote  string    `json:"snapshot_note"`
+	CurrentNote   string    `json:"current_note"`
+}
+
+// CreateSnapshot - copies a character's current sheet into a new named snapshot
+func CreateSnapshot(tx *pop.Connection, character models.Character, name string, createdBy UUID.UUID) (models.SheetSnapshot, *validate.Errors, error) {
+	var existing []models.SheetSnapshot
+	if err := tx.Where("character_id = ?", character.ID).Where("name = ?", name).All(&existing); err != nil {
+		return models.SheetSnapshot{}, nil, errors.New(messages.ProblemGettingSnapshotsError)
+	}
+	if len(existing) > 0 {
+		return models.SheetSnapshot{}, nil, errors.New(messages.DuplicateSnapshotError)
+	}
+
+	current, err := GetSheet(tx, character.ID)
+	if err != nil {
+		return models.SheetSnapshot{}, nil, err
+	}
+
+	snapshot := models.SheetSnapshot{
+		CharacterID: character.ID,
+		Name:        name,
+		CreatedBy:   createdBy,
+		Entries:     []models.SheetSnapshotEntry{},
+	}
+	verrs, err := tx.ValidateAndCreate(&snapshot)
+	if err != nil {
+		return snapshot, verrs, errors.New(messages.UnknownError)
+	}
+	if verrs.HasAny() {
+		return snapshot, verrs, nil
+	}
+
+	for _, entry := range current {
+		snapshotEntry := models.SheetSnapshotEntry{
+			SnapshotID: snapshot.ID,
+			SkillID:    entry.SkillID,
+			Value:      entry.Value,
+			Note:       entry.Note,
+		}
+		if tx.Create(&snapshotEntry) != nil {
+			return snapshot, verrs, errors.New(messages.UnknownError)
+		}
+		snapshot.Entries = append(snapshot.Entries, snapshotEntry)
+	}
+	return snapshot, verrs, nil
+}
+
+// GetSnapshots - a character's snapshots, newest first, with their entries
+func GetSnapshots(tx *pop.Connection, characterID UUID.UUID) ([]models.SheetSnapshot, error) {
+	snapshots := []models.SheetSnapshot{}
+	if err := tx.Where("character_id = ?", characterID).Order("created_at desc").All(&snapshots); err != nil {
+		return snapshots, errors.New(messages.ProblemGettingSnapshotsError)
+	}
+	for i := range snapshots {
+		if err := loadSnapshotEntries(tx, &snapshots[i]); err != nil {
+			return snapshots, err
+		}
+	}
+	return snapshots, nil
+}
+
+// GetSnapshot - one of a character's snapshots, with its entries
+func GetSnapshot(tx *pop.Connection, characterID UUID.UUID, uuidString string) (models.SheetSnapshot, error) {
+	var snapshots []models.SheetSnapshot
+	err := tx.Where("character_id = ?", characterID).Where("id = ?", uuidString).All(&snapshots)
+	if err != nil || len(snapshots) == 0 {
+		return models.SheetSnapshot{}, errors.New(messages.SnapshotNotFoundError)
+	}
+	err = loadSnapshotEntries(tx, &snapshots[0])
+	return snapshots[0], err
+}
+
+func loadSnapshotEntries(tx *pop.Connection, snapshot *models.SheetSnapshot) error {
+	snapshot.Entries = []models.SheetSnapshotEntry{}
+	if err := tx.Where("snapshot_id = ?", snapshot.ID).All(&snapshot.Entries); err != nil {
+		return errors.New(messages.ProblemGettingSnapshotsError)
+	}
+	return nil
+}
+
+// DiffSnapshot - the skills whose value or note differs between a snapshot and the character's current sheet
+func DiffSnapshot(tx *pop.Connection, snapshot models.SheetSnapshot) ([]SheetDifference, error) {
+	current, err := GetSheet(tx, snapshot.CharacterID)
+	if err != nil {
+		return nil, err
+	}
+	return DiffSnapshotEntries(snapshot.Entries, current), nil
+}
+
+// DiffSnapshotEntries - the skills whose value or note differs between a snapshot's entries and a sheet
+func DiffSnapshotEntries(entries []models.SheetSnapshotEntry, current []models.CharacterSheetEntry) []SheetDifference {
+	differences := []SheetDifference{}
+	now := map[UUID.UUID]models.CharacterSheetEntry{}
+	for _, entry := range current {
+		now[entry.SkillID] = entry
+	}
+	then := map[UUID.UUID]bool{}
+	for _, entry := range entries {
+		then[entry.SkillID] = true
+		difference := SheetDifference{SkillID: entry.SkillID, SnapshotValue: nulls.NewInt(entry.Value), SnapshotNote: entry.Note}
+		if currentEntry, ok := now[entry.SkillID]; ok {
+			if currentEntry.Value == entry.Value && currentEntry.Note == entry.Note {
+				continue
+			}
+			difference.CurrentValue = nulls.NewInt(currentEntry.Value)
+			difference.CurrentNote = currentEntry.Note
+		}
+		differences = append(differences, difference)
+	}
+	for _, entry := range current {
+		if !then[entry.SkillID] {
+			differences = append(differences, SheetDifference{SkillID: entry.SkillID, CurrentValue: nulls.NewInt(entry.Value), CurrentNote: entry.Note})
+		}
+	}
+	return differences
+}
+
+// RestoreSnapshot - rewrites a character's sheet to match a snapshot. Entries the snapshot doesn't have are removed
+// and entries for skills that no longer exist are skipped. The restored sheet goes through the same checks as any
+// other change, so if it fails them nothing should be committed
+func RestoreSnapshot(tx *pop.Connection, character models.Character, snapshot models.SheetSnapshot, changedBy UUID.UUID) ([]models.CharacterSheetEntry, *validate.Errors, error) {
+	current, err := GetSheet(tx, character.ID)
+	if err != nil {
+		return nil, validate.NewErrors(), err
+	}
+	skills, err := GetSkillsByID(tx)
+	if err != nil {
+		return nil, validate.NewErrors(), err
+	}
+
+	wanted := map[UUID.UUID]models.SheetSnapshotEntry{}
+	for _, entry := range snapshot.Entries {
+		if _, ok := skills[entry.SkillID]; ok {
+			wanted[entry.SkillID] = entry
+		}
+	}
+
+	existing := map[UUID.UUID]models.CharacterSheetEntry{}
+	for _, entry := range current {
+		if _, ok := wanted[entry.SkillID]; !ok {
+			if err := DeleteSheetEntry(tx, entry, changedBy); err != nil {
+				return nil, validate.NewErrors(), err
+			}
+			continue
+		}
+		existing[entry.SkillID] = entry
+	}
+
+	entries := []models.CharacterSheetEntry{}
+	for _, snapshotEntry := range snapshot.Entries {
+		if _, ok := wanted[snapshotEntry.SkillID]; !ok {
+			continue
+		}
+		entry, ok := existing[snapshotEntry.SkillID]
+		if !ok {
+			entry = models.CharacterSheetEntry{CharacterID: character.ID, SkillID: snapshotEntry.SkillID}
+		}
+		entry.Value = snapshotEntry.Value
+		entry.Note = snapshotEntry.Note
+		entries = append(entries, entry)
+	}
+
+	verrs, err := SaveSheetEntries(tx, character, entries, changedBy)
+	return entries, verrs, err
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/nulls"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+func Test_DiffSnapshotEntries(t *testing.T) {
+	swords := UUID.Must(UUID.NewV4())
+	archery := UUID.Must(UUID.NewV4())
+
+	cases := []struct {
+		name        string
+		snapshot    []models.SheetSnapshotEntry
+		current     []models.CharacterSheetEntry
+		differences []SheetDifference
+	}{
+		{
+			"unchanged",
+			[]models.SheetSnapshotEntry{{SkillID: swords, Value: 3, Note: "longsword"}},
+			[]models.CharacterSheetEntry{{SkillID: swords, Value: 3, Note: "longsword"}},
+			[]SheetDifference{},
+		},
+		{
+			"value changed",
+			[]models.SheetSnapshotEntry{{SkillID: swords, Value: 3}},
+			[]models.CharacterSheetEntry{{SkillID: swords, Value: 5}},
+			[]SheetDifference{{SkillID: swords, SnapshotValue: nulls.NewInt(3), CurrentValue: nulls.NewInt(5)}},
+		},
+		{
+			"note changed",
+			[]models.SheetSnapshotEntry{{SkillID: swords, Value: 3, Note: "longsword"}},
+			[]models.CharacterSheetEntry{{SkillID: swords, Value: 3, Note: "rapier"}},
+			[]SheetDifference{{SkillID: swords, SnapshotValue: nulls.NewInt(3), CurrentValue: nulls.NewInt(3), SnapshotNote: "longsword", CurrentNote: "rapier"}},
+		},
+		{
+			"removed since",
+			[]models.SheetSnapshotEntry{{SkillID: swords, Value: 3}},
+			[]models.CharacterSheetEntry{},
+			[]SheetDifference{{SkillID: swords, SnapshotValue: nulls.NewInt(3)}},
+		},
+		{
+			"added since",
+			[]models.SheetSnapshotEntry{},
+			[]models.CharacterSheetEntry{{SkillID: archery, Value: 1}},
+			[]SheetDifference{{SkillID: archery, CurrentValue: nulls.NewInt(1)}},
+		},
+	}
+	for _, tc := range cases {
+		differences := DiffSnapshotEntries(tc.snapshot, tc.current)
+		if len(differences) != len(tc.differences) {
+			t.Errorf("%s: expected %d differences, got %d", tc.name, len(tc.differences), len(differences))
+			continue
+		}
+		for i, want := range tc.differences {
+			if differences[i] != want {
+				t.Errorf("%s: expected %+v, got %+v", tc.name, want, differences[i])
+			}
+		}
+	}
+}
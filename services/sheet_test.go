@@ -0,0 +1,240 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/nulls"
+	UUID "github.com/gobuffalo/uuid"
+	"github.com/gobuffalo/validate"
+)
+
+func Test_EntryCost(t *testing.T) {
+	skill := models.Skill{Name: "Swords", StartingValue: 2, Cost: 3}
+	cases := []struct {
+		value int
+		cost  int
+	}{
+		{2, 0},
+		{3, 3},
+		{5, 9},
+		{1, -3},
+		{0, -6},
+	}
+	for _, tc := range cases {
+		if got := EntryCost(skill, tc.value); got != tc.cost {
+			t.Errorf("Swords at %d: expected %d, got %d", tc.value, tc.cost, got)
+		}
+	}
+}
+
+func Test_SpentPoints(t *testing.T) {
+	swords := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Swords", StartingValue: 1, Cost: 2}
+	archery := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Archery", StartingValue: 0, Cost: 1}
+	combat := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Combat", Cost: 5, ChildRule: models.ChildRuleSum}
+	skills := map[UUID.UUID]models.Skill{swords.ID: swords, archery.ID: archery, combat.ID: combat}
+	unknown := UUID.Must(UUID.NewV4())
+
+	cases := []struct {
+		name  string
+		sheet []models.CharacterSheetEntry
+		spent int
+	}{
+		{"empty", []models.CharacterSheetEntry{}, 0},
+		{"at starting values", []models.CharacterSheetEntry{{SkillID: swords.ID, Value: 1}, {SkillID: archery.ID, Value: 0}}, 0},
+		{"raised", []models.CharacterSheetEntry{{SkillID: swords.ID, Value: 4}, {SkillID: archery.ID, Value: 3}}, 9},
+		{"lowered gives points back", []models.CharacterSheetEntry{{SkillID: swords.ID, Value: 0}, {SkillID: archery.ID, Value: 3}}, 1},
+		{"derived skills are free", []models.CharacterSheetEntry{{SkillID: combat.ID, Value: 10}, {SkillID: archery.ID, Value: 2}}, 2},
+		{"unknown skills are free", []models.CharacterSheetEntry{{SkillID: unknown, Value: 10}}, 0},
+	}
+	for _, tc := range cases {
+		if got := SpentPoints(skills, tc.sheet); got != tc.spent {
+			t.Errorf("%s: expected %d, got %d", tc.name, tc.spent, got)
+		}
+	}
+}
+
+func Test_CheckBudget(t *testing.T) {
+	swords := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Swords", Cost: 2}
+	archery := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Archery", Cost: 1}
+	skills := map[UUID.UUID]models.Skill{swords.ID: swords, archery.ID: archery}
+	swordsEntry := models.CharacterSheetEntry{ID: UUID.Must(UUID.NewV4()), SkillID: swords.ID, Value: 3}
+	archeryEntry := models.CharacterSheetEntry{ID: UUID.Must(UUID.NewV4()), SkillID: archery.ID, Value: 2}
+	current := []models.CharacterSheetEntry{swordsEntry, archeryEntry}
+	with := func(entry models.CharacterSheetEntry, value int) models.CharacterSheetEntry {
+		entry.Value = value
+		return entry
+	}
+
+	cases := []struct {
+		name     string
+		budget   nulls.Int
+		current  []models.CharacterSheetEntry
+		changes  []models.CharacterSheetEntry
+		budgeted []string
+		entries  []string
+	}{
+		{"no budget", nulls.Int{}, current, []models.CharacterSheetEntry{with(swordsEntry, 50)}, nil, nil},
+		{"within budget", nulls.NewInt(10), current, []models.CharacterSheetEntry{with(swordsEntry, 4)}, nil, nil},
+		{"exactly the budget", nulls.NewInt(10), current, []models.CharacterSheetEntry{with(swordsEntry, 4), with(archeryEntry, 2)}, nil, nil},
+		{
+			"over budget names what got dearer", nulls.NewInt(10), current,
+			[]models.CharacterSheetEntry{with(swordsEntry, 5), with(archeryEntry, 1)},
+			[]string{fmt.Sprintf(messages.OverBudgetError, 11, 10)},
+			[]string{fmt.Sprintf(messages.EntryCostError, "Swords", 5, 10, 0, 2)},
+		},
+		{
+			"new entries count", nulls.NewInt(8), current,
+			[]models.CharacterSheetEntry{{SkillID: archery.ID, Value: 1}},
+			[]string{fmt.Sprintf(messages.OverBudgetError, 9, 8)},
+			[]string{fmt.Sprintf(messages.EntryCostError, "Archery", 1, 1, 0, 1)},
+		},
+		{"already over and spending less", nulls.NewInt(4), current, []models.CharacterSheetEntry{with(swordsEntry, 2)}, nil, nil},
+		{"already over and spending the same", nulls.NewInt(4), current, []models.CharacterSheetEntry{with(swordsEntry, 2), with(archeryEntry, 4)}, nil, nil},
+		{
+			"already over and spending more", nulls.NewInt(4), current,
+			[]models.CharacterSheetEntry{with(archeryEntry, 3)},
+			[]string{fmt.Sprintf(messages.OverBudgetError, 9, 4)},
+			[]string{fmt.Sprintf(messages.EntryCostError, "Archery", 3, 3, 0, 1)},
+		},
+	}
+	for _, tc := range cases {
+		verrs := validate.NewErrors()
+		character := models.Character{PointBudget: tc.budget}
+		proposed := ProposedSheet(tc.current, tc.changes)
+		checkBudget(verrs, character, skills, tc.current, proposed, tc.changes)
+		if got := verrs.Get("budget"); fmt.Sprint(got) != fmt.Sprint(tc.budgeted) {
+			t.Errorf("%s: expected budget errors %q, got %q", tc.name, tc.budgeted, got)
+		}
+		if got := verrs.Get("entries"); fmt.Sprint(got) != fmt.Sprint(tc.entries) {
+			t.Errorf("%s: expected entry errors %q, got %q", tc.name, tc.entries, got)
+		}
+	}
+}
+
+func Test_DeriveValues(t *testing.T) {
+	combat := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Combat", ChildRule: models.ChildRuleSum}
+	melee := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Melee", ParentSkillID: combat.ID, ChildRule: models.ChildRuleAverage}
+	swords := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Swords", ParentSkillID: melee.ID}
+	axes := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Axes", ParentSkillID: melee.ID}
+	archery := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Archery", ParentSkillID: combat.ID}
+	brawling := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Brawling", ParentSkillID: combat.ID}
+	magic := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Magic", ChildRule: models.ChildRuleAverage}
+	fire := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Fire", ParentSkillID: magic.ID}
+	lore := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Lore", ChildRule: models.ChildRuleCapped}
+	history := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "History", ParentSkillID: lore.ID}
+	skills := map[UUID.UUID]models.Skill{}
+	for _, skill := range []models.Skill{combat, melee, swords, axes, archery, brawling, magic, fire, lore, history} {
+		skills[skill.ID] = skill
+	}
+
+	cases := []struct {
+		name   string
+		sheet  map[UUID.UUID]int
+		expect map[UUID.UUID]int
+	}{
+		{
+			"sum",
+			map[UUID.UUID]int{combat.ID: 0, brawling.ID: 3, archery.ID: 4},
+			map[UUID.UUID]int{combat.ID: 7},
+		},
+		{
+			"average rounds down",
+			map[UUID.UUID]int{melee.ID: 0, swords.ID: 3, axes.ID: 4},
+			map[UUID.UUID]int{melee.ID: 3},
+		},
+		{
+			"nested",
+			map[UUID.UUID]int{combat.ID: 0, melee.ID: 0, swords.ID: 2, axes.ID: 4, archery.ID: 5},
+			map[UUID.UUID]int{melee.ID: 3, combat.ID: 8},
+		},
+		{
+			"subskills under a derived skill that isn't on the sheet don't count",
+			map[UUID.UUID]int{combat.ID: 0, swords.ID: 3, archery.ID: 4},
+			map[UUID.UUID]int{combat.ID: 4},
+		},
+		{
+			"only subskills on the sheet count",
+			map[UUID.UUID]int{melee.ID: 0, axes.ID: 5},
+			map[UUID.UUID]int{melee.ID: 5},
+		},
+		{
+			"no subskills on the sheet keeps the value",
+			map[UUID.UUID]int{magic.ID: 6},
+			map[UUID.UUID]int{magic.ID: 6},
+		},
+		{
+			"capped parents aren't derived",
+			map[UUID.UUID]int{lore.ID: 2, history.ID: 5},
+			map[UUID.UUID]int{lore.ID: 2, history.ID: 5},
+		},
+	}
+	for _, tc := range cases {
+		sheet := []models.CharacterSheetEntry{}
+		for skillID, value := range tc.sheet {
+			sheet = append(sheet, models.CharacterSheetEntry{SkillID: skillID, Value: value})
+		}
+		derived := DeriveValues(skills, sheet)
+		if len(derived) != len(sheet) {
+			t.Errorf("%s: expected %d entries, got %d", tc.name, len(sheet), len(derived))
+		}
+		for _, entry := range derived {
+			if want, ok := tc.expect[entry.SkillID]; ok && entry.Value != want {
+				t.Errorf("%s: expected %s to be %d, got %d", tc.name, skills[entry.SkillID].Name, want, entry.Value)
+			}
+		}
+	}
+}
+
+func Test_CheckChildRules(t *testing.T) {
+	lore := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Lore", ChildRule: models.ChildRuleCapped}
+	history := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "History", ParentSkillID: lore.ID}
+	myths := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Myths", ParentSkillID: lore.ID}
+	crafts := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Crafts"}
+	smithing := models.Skill{ID: UUID.Must(UUID.NewV4()), Name: "Smithing", ParentSkillID: crafts.ID}
+	skills := map[UUID.UUID]models.Skill{}
+	for _, skill := range []models.Skill{lore, history, myths, crafts, smithing} {
+		skills[skill.ID] = skill
+	}
+
+	cases := []struct {
+		name    string
+		sheet   map[UUID.UUID]int
+		changed []UUID.UUID
+		errors  []string
+	}{
+		{"at the parent's value", map[UUID.UUID]int{lore.ID: 3, history.ID: 3}, []UUID.UUID{history.ID}, nil},
+		{
+			"subskill raised above the parent",
+			map[UUID.UUID]int{lore.ID: 3, history.ID: 4},
+			[]UUID.UUID{history.ID},
+			[]string{fmt.Sprintf(messages.AboveParentError, "History", 4, "Lore", 3)},
+		},
+		{
+			"parent lowered below its subskills",
+			map[UUID.UUID]int{lore.ID: 1, history.ID: 2, myths.ID: 1},
+			[]UUID.UUID{lore.ID},
+			[]string{fmt.Sprintf(messages.AboveParentError, "History", 2, "Lore", 1)},
+		},
+		{"breaches nobody touched aren't reported", map[UUID.UUID]int{lore.ID: 1, history.ID: 2, myths.ID: 0}, []UUID.UUID{myths.ID}, nil},
+		{"parent not on the sheet", map[UUID.UUID]int{history.ID: 9}, []UUID.UUID{history.ID}, nil},
+		{"parent without a rule", map[UUID.UUID]int{crafts.ID: 1, smithing.ID: 5}, []UUID.UUID{smithing.ID}, nil},
+	}
+	for _, tc := range cases {
+		proposed := []models.CharacterSheetEntry{}
+		for skillID, value := range tc.sheet {
+			proposed = append(proposed, models.CharacterSheetEntry{SkillID: skillID, Value: value})
+		}
+		changes := []models.CharacterSheetEntry{}
+		for _, skillID := range tc.changed {
+			changes = append(changes, models.CharacterSheetEntry{SkillID: skillID, Value: tc.sheet[skillID]})
+		}
+		verrs := validate.NewErrors()
+		checkChildRules(verrs, skills, proposed, changes)
+		if got := verrs.Get("entries"); fmt.Sprint(got) != fmt.Sprint(tc.errors) {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.errors, got)
+		}
+	}
+}
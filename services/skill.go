@@ -0,0 +1,181 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+// SkillDeleteRefuse - only delete a skill nothing uses
+const SkillDeleteRefuse = "refuse"
+
+// SkillDeleteCascade - delete a skill along with its subskills, all the way down, and every sheet entry for them
+const SkillDeleteCascade = "cascade"
+
+// SkillDeleteReparent - delete a skill and its sheet entries, moving its subskills up to its own parent
+const SkillDeleteReparent = "reparent"
+
+// SkillDeleteModes - every way a skill can be deleted
+var SkillDeleteModes = []string{SkillDeleteRefuse, SkillDeleteCascade, SkillDeleteReparent}
+
+// SkillUsage - what depends on a skill
+type SkillUsage struct {
+	SheetEntries  int `json:"sheet_entries"`
+	Subskills     int `json:"subskills"`
+	Prerequisites int `json:"prerequisites"`
+	Encounters    int `json:"encounters"`
+}
+
+// InUse - whether anything depends on the skill
+func (u SkillUsage) InUse() bool {
+	return u.SheetEntries > 0 || u.Subskills > 0 || u.Prerequisites > 0 || u.Encounters > 0
+}
+
+// SkillDeletion - a report of everything a skill deletion touched
+type SkillDeletion struct {
+	Mode                 string      `json:"mode"`
+	DeletedSkills        []UUID.UUID `json:"deleted_skills"`
+	ReparentedSkills     []UUID.UUID `json:"reparented_skills"`
+	DeletedSheetEntries  int         `json:"deleted_sheet_entries"`
+	DeletedPrerequisites int         `json:"deleted_prerequisites"`
+	ClearedEncounters    []UUID.UUID `json:"cleared_encounters"`
+	Usage                SkillUsage  `json:"usage"`
+}
+
+// GetSkillUsage - counts what depends on a skill. Finished encounters don't count, they're history
+func GetSkillUsage(tx *pop.Connection, skill models.Skill) (SkillUsage, error) {
+	usage := SkillUsage{}
+	var err error
+	if usage.SheetEntries, err = tx.Where("skill_id = ?", skill.ID).Count(&models.CharacterSheetEntry{}); err != nil {
+		return usage, errors.New(messages.ProblemGettingSheetEntryError)
+	}
+	if usage.Subskills, err = tx.Where("parent_skill_id = ?", skill.ID).Count(&models.Skill{}); err != nil {
+		return usage, errors.New(messages.ProblemGettingSkillsError)
+	}
+	if usage.Prerequisites, err = tx.Where("skill_id = ? or required_skill_id = ?", skill.ID, skill.ID).Count(&models.SkillPrerequisite{}); err != nil {
+		return usage, errors.New(messages.ProblemGettingPrerequisitesError)
+	}
+	encounters, err := getEncountersUsingSkill(tx, skill.ID)
+	if err != nil {
+		return usage, err
+	}
+	usage.Encounters = len(encounters)
+	return usage, nil
+}
+
+func getEncountersUsingSkill(tx *pop.Connection, skillID UUID.UUID) ([]models.Encounter, error) {
+	encounters := []models.Encounter{}
+	err := tx.Where("status != ?", models.EncounterFinished).
+		Where("initiative_skill_id = ? or health_skill_id = ?", skillID, skillID).
+		All(&encounters)
+	if err != nil {
+		return encounters, errors.New(messages.ProblemGettingEncountersError)
+	}
+	return encounters, nil
+}
+
+// DeleteSkill - deletes a skill the way the mode says, reporting what was affected.
+// Past rolls and attacks keep pointing at the skill so fight history stays whole, and removed sheet entries are kept in the sheet history
+func DeleteSkill(tx *pop.Connection, skill models.Skill, mode string, changedBy UUID.UUID) (SkillDeletion, error) {
+	deletion := SkillDeletion{Mode: mode, DeletedSkills: []UUID.UUID{}, ReparentedSkills: []UUID.UUID{}, ClearedEncounters: []UUID.UUID{}}
+
+	usage, err := GetSkillUsage(tx, skill)
+	if err != nil {
+		return deletion, err
+	}
+	deletion.Usage = usage
+
+	var doomed []models.Skill
+	switch mode {
+	case SkillDeleteRefuse:
+		if usage.InUse() {
+			return deletion, errors.New(messages.SkillInUseError)
+		}
+		doomed = []models.Skill{skill}
+	case SkillDeleteCascade:
+		if doomed, err = getDescendants(tx, skill); err != nil {
+			return deletion, err
+		}
+	case SkillDeleteReparent:
+		var children []models.Skill
+		if err := tx.Where("parent_skill_id = ?", skill.ID).All(&children); err != nil {
+			return deletion, errors.New(messages.ProblemGettingSkillsError)
+		}
+		for i := range children {
+			children[i].ParentSkillID = skill.ParentSkillID
+			if tx.Save(&children[i]) != nil {
+				return deletion, errors.New(messages.UnknownError)
+			}
+			deletion.ReparentedSkills = append(deletion.ReparentedSkills, children[i].ID)
+		}
+		doomed = []models.Skill{skill}
+	default:
+		return deletion, errors.New(messages.UnknownSkillDeleteModeError)
+	}
+
+	for i := range doomed {
+		id := doomed[i].ID
+		var entries []models.CharacterSheetEntry
+		if err := tx.Where("skill_id = ?", id).All(&entries); err != nil {
+			return deletion, errors.New(messages.ProblemGettingSheetEntryError)
+		}
+		for _, entry := range entries {
+			if err := DeleteSheetEntry(tx, entry, changedBy); err != nil {
+				return deletion, err
+			}
+		}
+		deletion.DeletedSheetEntries += len(entries)
+
+		count, err := tx.RawQuery("delete from skill_prerequisites where skill_id = ? or required_skill_id = ?", id, id).ExecWithCount()
+		if err != nil {
+			return deletion, errors.New(messages.UnknownError)
+		}
+		deletion.DeletedPrerequisites += count
+
+		encounters, err := getEncountersUsingSkill(tx, id)
+		if err != nil {
+			return deletion, err
+		}
+		for j := range encounters {
+			if encounters[j].InitiativeSkillID == id {
+				encounters[j].InitiativeSkillID = UUID.Nil
+			}
+			if encounters[j].HealthSkillID == id {
+				encounters[j].HealthSkillID = UUID.Nil
+			}
+			if tx.Save(&encounters[j]) != nil {
+				return deletion, errors.New(messages.UnknownError)
+			}
+			deletion.ClearedEncounters = append(deletion.ClearedEncounters, encounters[j].ID)
+		}
+
+		if tx.Destroy(&doomed[i]) != nil {
+			return deletion, errors.New(messages.UnknownError)
+		}
+		deletion.DeletedSkills = append(deletion.DeletedSkills, id)
+	}
+	return deletion, nil
+}
+
+// getDescendants - a skill and every subskill under it, at any depth
+func getDescendants(tx *pop.Connection, skill models.Skill) ([]models.Skill, error) {
+	var skills []models.Skill
+	if err := tx.All(&skills); err != nil {
+		return nil, errors.New(messages.ProblemGettingSkillsError)
+	}
+
+	descendants := []models.Skill{skill}
+	seen := map[UUID.UUID]bool{skill.ID: true}
+	for i := 0; i < len(descendants); i++ {
+		for _, s := range skills {
+			if s.ParentSkillID == descendants[i].ID && !seen[s.ID] {
+				seen[s.ID] = true
+				descendants = append(descendants, s)
+			}
+		}
+	}
+	return descendants, nil
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+// GetSkillSet - returns a skill set by ID; no ID means the default set
+func GetSkillSet(tx *pop.Connection, skillSetID UUID.UUID) (models.SkillSet, error) {
+	if skillSetID == UUID.Nil {
+		skillSetID = models.DefaultSkillSetID
+	}
+	var skillSets []models.SkillSet
+	err := tx.Where("id = ?", skillSetID).All(&skillSets)
+	if err != nil || len(skillSets) == 0 {
+		return models.SkillSet{}, errors.New(messages.SkillSetNotFoundError)
+	}
+	return skillSets[0], nil
+}
+
+// GetSetSkills - every skill in a skill set
+func GetSetSkills(tx *pop.Connection, skillSetID UUID.UUID) ([]models.Skill, error) {
+	skills := []models.Skill{}
+	if err := tx.Where("skill_set_id = ?", skillSetID).All(&skills); err != nil {
+		return skills, errors.New(messages.ProblemGettingSkillsError)
+	}
+	return skills, nil
+}
+
+// CheckSkillPlacement - a skill's parent has to be in the same skill set as the skill
+func CheckSkillPlacement(tx *pop.Connection, skill models.Skill) error {
+	if _, err := GetSkillSet(tx, skill.SkillSetID); err != nil {
+		return err
+	}
+	if skill.ParentSkillID == UUID.Nil {
+		return nil
+	}
+	parent, err := FindSkill(tx, UUID.Nil, skill.ParentSkillID, "")
+	if err != nil {
+		return err
+	}
+	if parent.SkillSetID != skill.SkillSetID {
+		return errors.New(messages.ParentInOtherSkillSetError)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+// SkillNode - a skill with its subskills and, when asked for, a character's entry for it
+type SkillNode struct {
+	models.Skill
+	Entry    *models.CharacterSheetEntry `json:"entry,omitempty"`
+	Children []SkillNode                 `json:"children"`
+}
+
+// SkillCycle - the skills that are their own ancestors and so can't be placed in a tree
+type SkillCycle struct {
+	Skills []string
+}
+
+func (e *SkillCycle) Error() string {
+	return fmt.Sprintf(messages.SkillCycleError, strings.Join(e.Skills, ", "))
+}
+
+// BuildSkillTree - nests skills under their parents, at any depth, sorted by name.
+// Skills whose parent doesn't exist sit at the top. Skills that are their own ancestors can't be placed anywhere, so they're a *SkillCycle error
+func BuildSkillTree(skills []models.Skill, sheet []models.CharacterSheetEntry) ([]SkillNode, error) {
+	byID := map[UUID.UUID]models.Skill{}
+	for _, skill := range skills {
+		byID[skill.ID] = skill
+	}
+	entries := map[UUID.UUID]models.CharacterSheetEntry{}
+	for _, entry := range sheet {
+		entries[entry.SkillID] = entry
+	}
+
+	children := map[UUID.UUID][]models.Skill{}
+	roots := []models.Skill{}
+	for _, skill := range skills {
+		if _, ok := byID[skill.ParentSkillID]; ok && skill.ParentSkillID != UUID.Nil {
+			children[skill.ParentSkillID] = append(children[skill.ParentSkillID], skill)
+		} else {
+			roots = append(roots, skill)
+		}
+	}
+
+	placed := map[UUID.UUID]bool{}
+	var build func(list []models.Skill) []SkillNode
+	build = func(list []models.Skill) []SkillNode {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+		nodes := []SkillNode{}
+		for _, skill := range list {
+			placed[skill.ID] = true
+			node := SkillNode{Skill: skill, Children: build(children[skill.ID])}
+			if entry, ok := entries[skill.ID]; ok {
+				node.Entry = &entry
+			}
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+	tree := build(roots)
+
+	if len(placed) < len(skills) {
+		names := []string{}
+		for _, skill := range skills {
+			if !placed[skill.ID] {
+				names = append(names, skill.Name)
+			}
+		}
+		sort.Strings(names)
+		return tree, &SkillCycle{Skills: names}
+	}
+	return tree, nil
+}
+
+// CreatesCycle - whether making parentID the parent of skillID would make the skill its own ancestor
+func CreatesCycle(tx *pop.Connection, skillID UUID.UUID, parentID UUID.UUID) (bool, error) {
+	skills, err := GetSkillsByID(tx)
+	if err != nil {
+		return false, err
+	}
+	return createsCycle(skills, skillID, parentID), nil
+}
+
+// createsCycle - walks up from parentID until it runs out of parents, finds the skill or goes round in circles
+func createsCycle(skills map[UUID.UUID]models.Skill, skillID UUID.UUID, parentID UUID.UUID) bool {
+	seen := map[UUID.UUID]bool{}
+	for id := parentID; id != UUID.Nil; id = skills[id].ParentSkillID {
+		if id == skillID || seen[id] {
+			return true
+		}
+		seen[id] = true
+		if _, ok := skills[id]; !ok {
+			return false
+		}
+	}
+	return false
+}
+
+// GetSkillTree - the skill hierarchy of a skill set (or of every set), with a character's sheet values if a character is given
+func GetSkillTree(tx *pop.Connection, skillSetID UUID.UUID, characterID UUID.UUID) ([]SkillNode, error) {
+	skills := []models.Skill{}
+	query := tx.Where("1=1")
+	if skillSetID != UUID.Nil {
+		query = query.Where("skill_set_id = ?", skillSetID)
+	}
+	if err := query.All(&skills); err != nil {
+		return nil, errors.New(messages.ProblemGettingSkillsError)
+	}
+
+	sheet := []models.CharacterSheetEntry{}
+	if characterID != UUID.Nil {
+		var err error
+		if sheet, err = GetSheet(tx, characterID); err != nil {
+			return nil, err
+		}
+		byID := map[UUID.UUID]models.Skill{}
+		for _, skill := range skills {
+			byID[skill.ID] = skill
+		}
+		sheet = DeriveValues(byID, sheet)
+	}
+	return BuildSkillTree(skills, sheet)
+}
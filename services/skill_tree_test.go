@@ -0,0 +1,123 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	UUID "github.com/gobuffalo/uuid"
+)
+
+func newSkill(name string, parent UUID.UUID) models.Skill {
+	return models.Skill{ID: UUID.Must(UUID.NewV4()), Name: name, ParentSkillID: parent}
+}
+
+// outline - a tree as "name(children...)" so whole shapes can be compared at once
+func outline(nodes []SkillNode) string {
+	parts := []string{}
+	for _, node := range nodes {
+		part := node.Name
+		if len(node.Children) > 0 {
+			part += "(" + outline(node.Children) + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+func Test_BuildSkillTree(t *testing.T) {
+	combat := newSkill("Combat", UUID.Nil)
+	melee := newSkill("Melee", combat.ID)
+	swords := newSkill("Swords", melee.ID)
+	longsword := newSkill("Longsword", swords.ID)
+	archery := newSkill("Archery", combat.ID)
+	orphan := newSkill("Orphan", UUID.Must(UUID.NewV4()))
+	magic := newSkill("Magic", UUID.Nil)
+	loopA := newSkill("Loop A", UUID.Nil)
+	loopB := newSkill("Loop B", loopA.ID)
+	loopA.ParentSkillID = loopB.ID
+
+	cases := []struct {
+		name    string
+		skills  []models.Skill
+		outline string
+		err     string
+	}{
+		{"empty", []models.Skill{}, "", ""},
+		{"flat", []models.Skill{magic, combat}, "Combat Magic", ""},
+		{"nested four deep", []models.Skill{longsword, swords, melee, combat, archery}, "Combat(Archery Melee(Swords(Longsword)))", ""},
+		{"orphans sit at the top", []models.Skill{orphan, melee, combat}, "Combat(Melee) Orphan", ""},
+		{"missing parent makes a new root", []models.Skill{swords, longsword}, "Swords(Longsword)", ""},
+		{"cycle", []models.Skill{loopA, loopB, magic}, "Magic", fmt.Sprintf(messages.SkillCycleError, "Loop A, Loop B")},
+	}
+	for _, tc := range cases {
+		tree, err := BuildSkillTree(tc.skills, nil)
+		if got := outline(tree); got != tc.outline {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.outline, got)
+		}
+		if tc.err == "" && err != nil {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+		}
+		if tc.err != "" && (err == nil || err.Error() != tc.err) {
+			t.Errorf("%s: expected error %q, got %v", tc.name, tc.err, err)
+		}
+		if _, ok := err.(*SkillCycle); tc.err != "" && !ok {
+			t.Errorf("%s: expected a *SkillCycle, got %T", tc.name, err)
+		}
+	}
+}
+
+func Test_BuildSkillTree_Entries(t *testing.T) {
+	combat := newSkill("Combat", UUID.Nil)
+	melee := newSkill("Melee", combat.ID)
+	sheet := []models.CharacterSheetEntry{{SkillID: melee.ID, Value: 7}}
+
+	tree, err := BuildSkillTree([]models.Skill{combat, melee}, sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree[0].Entry != nil {
+		t.Errorf("expected no entry for Combat, got %v", tree[0].Entry)
+	}
+	if entry := tree[0].Children[0].Entry; entry == nil || entry.Value != 7 {
+		t.Errorf("expected Melee's entry with 7, got %v", entry)
+	}
+}
+
+func Test_CreatesCycle(t *testing.T) {
+	combat := newSkill("Combat", UUID.Nil)
+	melee := newSkill("Melee", combat.ID)
+	swords := newSkill("Swords", melee.ID)
+	magic := newSkill("Magic", UUID.Nil)
+	loopA := newSkill("Loop A", UUID.Nil)
+	loopB := newSkill("Loop B", loopA.ID)
+	loopA.ParentSkillID = loopB.ID
+
+	skills := map[UUID.UUID]models.Skill{}
+	for _, skill := range []models.Skill{combat, melee, swords, magic, loopA, loopB} {
+		skills[skill.ID] = skill
+	}
+
+	cases := []struct {
+		name   string
+		skill  UUID.UUID
+		parent UUID.UUID
+		cycle  bool
+	}{
+		{"no parent", combat.ID, UUID.Nil, false},
+		{"unrelated parent", combat.ID, magic.ID, false},
+		{"moving under an ancestor", swords.ID, combat.ID, false},
+		{"unknown parent", combat.ID, UUID.Must(UUID.NewV4()), false},
+		{"own parent", combat.ID, combat.ID, true},
+		{"under its child", combat.ID, melee.ID, true},
+		{"under its grandchild", combat.ID, swords.ID, true},
+		{"under a skill that's already in a loop", magic.ID, loopB.ID, true},
+	}
+	for _, tc := range cases {
+		if got := createsCycle(skills, tc.skill, tc.parent); got != tc.cycle {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.cycle, got)
+		}
+	}
+}
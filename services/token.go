@@ -1,40 +1,149 @@
 package services
 
 import (
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"log"
+	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/dosaki/emote_combat_server/messages"
+	"github.com/dosaki/emote_combat_server/models"
+	"github.com/dosaki/emote_combat_server/services/keys"
+	"github.com/dosaki/emote_combat_server/services/revocation"
 	"github.com/gobuffalo/envy"
+	"github.com/gobuffalo/pop"
+	UUID "github.com/gobuffalo/uuid"
 )
 
-var invalidTokens = []string{}
+// Keys - what tokens are signed with (JWT_KEY_PATH, named JWT_KEY_ID) and checked against,
+// including older keys from the comma separated JWT_VERIFY_KEY_PATHS so they can be rotated out gently
+var Keys = keys.New(keys.Config{
+	SigningKeyPath:       envy.Get("JWT_KEY_PATH", ""),
+	SigningKeyID:         envy.Get("JWT_KEY_ID", ""),
+	VerificationKeyPaths: keys.SplitPaths(envy.Get("JWT_VERIFY_KEY_PATHS", "")),
+})
 
-// TokenIsValid - Checks against a list to make sure a token is valid
-func TokenIsValid(token string) bool {
-	for i := range invalidTokens {
-		if invalidTokens[i] == token {
-			return false
+// RevocationPruneInterval - how often expired tokens are cleared out of the revocation store
+var RevocationPruneInterval = 10 * time.Minute
+
+// Revocations - where logged out tokens are kept until they expire
+var Revocations revocation.Store = revocation.WithPruning(&DBRevocationStore{DB: models.DB}, RevocationPruneInterval)
+
+// DBRevocationStore - keeps revoked tokens in the database so they stay revoked across restarts and between instances
+type DBRevocationStore struct {
+	DB *pop.Connection
+}
+
+// Revoke - remembers a token as revoked until it expires
+func (s *DBRevocationStore) Revoke(token string, expiresAt time.Time) error {
+	revoked, err := s.IsRevoked(token)
+	if err != nil || revoked {
+		return err
+	}
+	entry := models.RevokedToken{TokenHash: revocation.Hash(token), ExpiresAt: expiresAt}
+	if s.DB.Create(&entry) != nil {
+		// someone else may have revoked it at the same time
+		if revoked, err := s.IsRevoked(token); err == nil && revoked {
+			return nil
 		}
+		return errors.New(messages.UnknownError)
 	}
-	return true
+	return nil
 }
 
-// InvalidateToken - adds token to the list of invalid tokens
-func InvalidateToken(token string) {
-	invalidTokens = append(invalidTokens, token)
-	fmt.Println(invalidTokens)
+// IsRevoked - whether a token has been revoked
+func (s *DBRevocationStore) IsRevoked(token string) (bool, error) {
+	exists, err := s.DB.Where("token_hash = ?", revocation.Hash(token)).Exists(&models.RevokedToken{})
+	if err != nil {
+		return false, errors.New(messages.ProblemCheckingTokenError)
+	}
+	return exists, nil
 }
 
-func ParseToken(token *jwt.Token) (interface{}, error) {
-	// check signing method
-	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-		return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
+// Prune - forgets every token that has expired by now
+func (s *DBRevocationStore) Prune(now time.Time) (int, error) {
+	pruned, err := s.DB.RawQuery("delete from revoked_tokens where expires_at <= ?", now).ExecWithCount()
+	if err != nil {
+		return 0, errors.New(messages.ProblemCheckingTokenError)
+	}
+	return pruned, nil
+}
+
+// TokenIsValid - Checks the token hasn't been revoked. If that can't be checked the token isn't trusted
+func TokenIsValid(token string) bool {
+	revoked, err := Revocations.IsRevoked(token)
+	if err != nil {
+		log.Println(err)
+		return false
+	}
+	return !revoked
+}
+
+// LoginIsRevoked - whether the login an access token came from has ended, by logging out or resetting the password,
+// which revokes every refresh token it has. Tokens without a login are only checked against Revocations.
+// If that can't be checked the token isn't trusted
+func LoginIsRevoked(familyID UUID.UUID) bool {
+	if familyID == UUID.Nil {
+		return false
+	}
+	active, err := models.DB.Where("family_id = ?", familyID).Where("revoked_at is null").Exists(&models.RefreshToken{})
+	if err != nil {
+		log.Println(err)
+		return true
+	}
+	return !active
+}
+
+// InvalidateToken - revokes a token until it expires
+func InvalidateToken(token string, expiresAt time.Time) error {
+	return Revocations.Revoke(token, expiresAt)
+}
+
+// AccessClaims - what an access token says: who it belongs to (jti, for historical reasons) and which login it came from
+type AccessClaims struct {
+	jwt.StandardClaims
+	SessionID string `json:"sid,omitempty"`
+}
+
+// SignAccessToken - a new access token for a user, tied to the family of refresh tokens of their login
+func SignAccessToken(userID UUID.UUID, familyID UUID.UUID) (string, time.Time, error) {
+	expiry := time.Now().Add(AccessTokenLifetime())
+	claims := AccessClaims{
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expiry.Unix(),
+			Id:        userID.String(),
+		},
+		SessionID: familyID.String(),
 	}
-	// read the key
-	mySignedKey, err := ioutil.ReadFile(envy.Get("JWT_KEY_PATH", ""))
+	tokenString, err := Keys.Sign(claims)
 	if err != nil {
-		return nil, fmt.Errorf("could not open jwt key, %v", err)
+		return "", expiry, fmt.Errorf("could not sign token, %v", err)
 	}
-	return mySignedKey, nil
+	return tokenString, expiry, nil
+}
+
+// TokenSession - the refresh token family a parsed access token came from, if any
+func TokenSession(token *jwt.Token) UUID.UUID {
+	if claims, ok := token.Claims.(jwt.MapClaims); ok {
+		if sid, ok := claims["sid"].(string); ok {
+			return UUID.FromStringOrNil(sid)
+		}
+	}
+	return UUID.Nil
+}
+
+// TokenExpiry - when a parsed token expires. Tokens without an expiry are treated as lasting for years
+func TokenExpiry(token *jwt.Token) time.Time {
+	if claims, ok := token.Claims.(jwt.MapClaims); ok {
+		if exp, ok := claims["exp"].(float64); ok {
+			return time.Unix(int64(exp), 0)
+		}
+	}
+	return time.Now().AddDate(10, 0, 0)
+}
+
+// ParseToken - for jwt.Parse: the key a token says it was signed with, as long as it's one we still accept
+func ParseToken(token *jwt.Token) (interface{}, error) {
+	return Keys.Keyfunc(token)
 }
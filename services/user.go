@@ -2,8 +2,11 @@ package services
 
 import (
 	"errors"
+	"fmt"
 
+	"github.com/dosaki/emote_combat_server/messages"
 	"github.com/dosaki/emote_combat_server/models"
+	"github.com/gobuffalo/pop"
 	UUID "github.com/gobuffalo/uuid"
 )
 
@@ -21,3 +24,19 @@ func GetUserByUUID(uuidString string) (models.User, error) {
 
 	return models.User{}, errors.New("Unable to find user")
 }
+
+// SetUserRole - gives a user a different role
+func SetUserRole(tx *pop.Connection, user *models.User, role string) error {
+	known := false
+	for _, r := range models.Roles {
+		known = known || r == role
+	}
+	if !known {
+		return fmt.Errorf(messages.UnknownRoleError, role)
+	}
+	user.Role = role
+	if tx.Save(user) != nil {
+		return errors.New(messages.UnknownError)
+	}
+	return nil
+}